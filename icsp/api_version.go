@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // URLEndPoint export this constant
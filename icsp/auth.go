@@ -24,7 +24,6 @@ import (
 	"strings"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // URLEndPoint export this constant
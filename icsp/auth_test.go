@@ -6,7 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
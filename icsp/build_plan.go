@@ -6,7 +6,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // URLEndPoint(s) export this constant
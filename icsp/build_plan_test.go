@@ -5,7 +5,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,7 +41,7 @@ func TestGetBuildPlanByName(t *testing.T) {
 		data, err := c.GetBuildPlanByName("ProLiant OS - RHEL 7.0 x64 Scripted Install")
 		assert.NoError(t, err, "GetServers threw error -> %s, %+v\n", err, data)
 		if assert.NotNil(t, data.URI) {
-			log.Debug(fmt.Sprintf("plan uri: %v", data.URI))
+			log.Debugf("plan uri: %v", data.URI)
 		}
 
 	} else {
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // FailModeData stage const
@@ -96,7 +95,7 @@ func (dj DeploymentJobs) NewDeploymentJobs(bp []OSDBuildPlan, bpdata *OSDPersona
 
 // SubmitDeploymentJobs api call to deployment jobs
 func (c *ICSPClient) SubmitDeploymentJobs(dj DeploymentJobs) (jt *JobTask, err error) {
-	log.Info("Applying OS Build plan for ICSP")
+	log.Infof("Applying OS Build plan for ICSP")
 	var (
 		uri  = "/rest/os-deployment-jobs"
 		juri ODSUri
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
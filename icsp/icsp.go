@@ -26,7 +26,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // ICSPClient - wrapper class for icsp api's
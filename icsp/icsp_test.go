@@ -10,7 +10,6 @@ import (
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/testconfig"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -111,11 +110,11 @@ func TestCreateServer(t *testing.T) {
 		}
 		ip = d.Tc.GetTestData(d.Env, "IloIPAddress").(string)
 		serialNumber := d.Tc.GetTestData(d.Env, "FreeBladeSerialNumber").(string)
-		log.Debug("implements acceptance test for TestCreateServer")
+		log.Debugf("implements acceptance test for TestCreateServer")
 		s, err := c.GetServerBySerialNumber(serialNumber) // fake serial number
 		assert.NoError(t, err, "GetServerBySerialNumber fake threw error -> %s, %+v\n", err, s)
 		if os.Getenv("ONEVIEW_TEST_PROVISION") != "true" {
-			log.Info("env ONEVIEW_TEST_PROVISION != true for TestCreateServer")
+			log.Infof("env ONEVIEW_TEST_PROVISION != true for TestCreateServer")
 			log.Infof("Skipping test create for : %s, %s", serialNumber, ip)
 			return
 		}
@@ -131,7 +130,7 @@ func TestCreateServer(t *testing.T) {
 		// check if the server now exist
 	} else {
 		_, c = getTestDriverU()
-		log.Debug("implements unit test for TestCreateServer")
+		log.Debugf("implements unit test for TestCreateServer")
 		err := c.CreateServer("foo", "bar", "127.0.0.1", 443)
 		assert.Error(t, err, "CreateServer should throw error  -> %s\n", err)
 	}
@@ -156,14 +155,14 @@ func TestPreApplyDeploymentJobs(t *testing.T) {
 		serialNumber, macAddr string
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for ApplyDeploymentJobs")
+		log.Debugf("implements acceptance test for ApplyDeploymentJobs")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
 		}
 		if os.Getenv("ONEVIEW_TEST_PROVISION") != "true" {
-			log.Info("env ONEVIEW_TEST_PROVISION != true")
-			log.Info("Skipping FreeBlade testing")
+			log.Infof("env ONEVIEW_TEST_PROVISION != true")
+			log.Infof("Skipping FreeBlade testing")
 			serialNumber = d.Tc.GetTestData(d.Env, "SerialNumber").(string)
 			macAddr = d.Tc.GetTestData(d.Env, "MacAddr").(string)
 		} else {
@@ -213,7 +212,7 @@ func TestApplyDeploymentJobs(t *testing.T) {
 		serialNumber string
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for ApplyDeploymentJobs")
+		log.Debugf("implements acceptance test for ApplyDeploymentJobs")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -244,7 +243,7 @@ func TestApplyDeploymentJobs(t *testing.T) {
 		assert.Equal(t, "docker", testValue2.Value, "Should return the saved custom attribute")
 
 		if os.Getenv("ONEVIEW_TEST_PROVISION") != "true" {
-			log.Info("env ONEVIEW_TEST_PROVISION != ture for ApplyDeploymentJobs")
+			log.Infof("env ONEVIEW_TEST_PROVISION != ture for ApplyDeploymentJobs")
 			log.Infof("Skipping OS build for : %s, %s", osBuildPlan, serialNumber)
 			return
 		}
@@ -253,7 +252,7 @@ func TestApplyDeploymentJobs(t *testing.T) {
 	} else {
 		var s Server
 		_, c = getTestDriverU()
-		log.Debug("implements unit test for ApplyDeploymentJobs")
+		log.Debugf("implements unit test for ApplyDeploymentJobs")
 		_, err := c.ApplyDeploymentJobs("testbuildplan", nil, s)
 		assert.Error(t, err, "ApplyDeploymentJobs threw error -> %s, %+v\n", err, s)
 	}
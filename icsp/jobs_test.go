@@ -5,7 +5,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,7 +24,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // ODSUri  returned from create server for job uri task
@@ -47,8 +46,8 @@ func (jt *JobTask) NewJobTask(c *ICSPClient) *JobTask {
 	return &JobTask{
 		IsDone:   false,
 		Client:   c,
-		Timeout:  360, // default 1hr
-		WaitTime: 10}  // default 10sec, impacts Timeout
+		Timeout:  360,              // default 1hr
+		WaitTime: 10 * time.Second} // default 10sec, impacts Timeout
 }
 
 // Reset - reset job task
@@ -60,7 +59,7 @@ func (jt *JobTask) Reset() {
 func (jt *JobTask) GetCurrentStatus() error {
 	log.Debugf("Working on getting current job status")
 	if jt.JobURI.URI != "" {
-		log.Debugf(jt.JobURI.URI.String())
+		log.Debugf("job uri: %s", jt.JobURI.URI)
 		data, err := jt.Client.RestAPICall(rest.GET, jt.JobURI.URI.String(), nil)
 		if err != nil {
 			return err
@@ -142,11 +141,11 @@ func (jt *JobTask) Wait() error {
 				}
 			}
 		} else {
-			log.Info("Waiting on job creation.")
+			log.Infof("Waiting on job creation.")
 		}
 
 		// wait time before next check
-		time.Sleep(time.Millisecond * (1000 * jt.WaitTime)) // wait 10sec before checking the status again
+		time.Sleep(jt.WaitTime) // wait 10sec before checking the status again
 		currenttime++
 
 		// get the current status
@@ -156,13 +155,13 @@ func (jt *JobTask) Wait() error {
 		}
 	}
 	if !(currenttime < jt.Timeout) {
-		log.Warn("Task timed out.")
+		log.Warnf("Task timed out.")
 	}
 
 	if JOB_RUNNING_NO.Equal(jt.Running) {
 		log.Infof("Job, %s, completed", jt.GetComplettedStatus())
 	} else {
-		log.Warn("Job still running un-expected.")
+		log.Warnf("Job still running un-expected.")
 	}
 	jt.IsDone = true
 	return nil
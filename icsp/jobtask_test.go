@@ -3,8 +3,8 @@ package icsp
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,3 +20,10 @@ func TestODSUri(t *testing.T) {
 	err := json.Unmarshal([]byte(jsonJobURI), &u)
 	assert.NoError(t, err, "Unmarshal ODSUri for Job threw error -> %s, %+v\n", err, jsonJobURI)
 }
+
+// TestNewJobTaskWaitTime verify the default wait between job checks is 10 seconds
+func TestNewJobTaskWaitTime(t *testing.T) {
+	var jt *JobTask
+	jt = jt.NewJobTask(&ICSPClient{})
+	assert.Equal(t, 10*time.Second, jt.WaitTime)
+}
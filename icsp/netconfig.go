@@ -23,7 +23,6 @@ import (
 	"strings"
 
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // NetConfigInterface - part of NetCustomization type , describes interface configuration
@@ -110,21 +109,21 @@ func (n *NetConfig) NewNetConfigInterface(
 		VlanID:         vlandid,
 	}
 	if macaddr == "" {
-		log.Error("Network configuration (NetConfigInterface) requires a MAC Address to create a new interface object.")
+		log.Errorf("Network configuration (NetConfigInterface) requires a MAC Address to create a new interface object.")
 	}
 	if isipv6 {
 		if ipv6gateway.IsNil() {
-			log.Error("Gateway for ipv6 is required, configure IPv6Gateway")
+			log.Errorf("Gateway for ipv6 is required, configure IPv6Gateway")
 		}
 		inetconfig.IPv6Gateway = ipv6gateway.String()
 	}
 	if !isdhcp {
 		if ipv4gateway.IsNil() {
-			log.Error("Static ipv4 configuration requires a gateway configured (IPv4Gateway)")
+			log.Errorf("Static ipv4 configuration requires a gateway configured (IPv4Gateway)")
 		}
 		inetconfig.IPv4Gateway = ipv4gateway.String()
 		if staticnets.IsNil() {
-			log.Error("Static ipv4 configuration requires static network list")
+			log.Errorf("Static ipv4 configuration requires static network list")
 		}
 		inetconfig.StaticNetworks = strings.Split(staticnets.String(), SplitSep)
 	}
@@ -17,8 +17,6 @@ limitations under the License.
 // Package icsp -
 package icsp
 
-import "github.com/docker/machine/libmachine/log"
-
 // ValueItem struct
 type ValueItem struct {
 	Scope string `json:"scope,omitempty"` // scope of value
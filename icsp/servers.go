@@ -25,7 +25,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // URLEndPoint export this constant
@@ -382,10 +381,10 @@ type ServerCreate struct {
 // NewServerCreate make a new servercreate object
 func (sc ServerCreate) NewServerCreate(user string, pass string, ip string, port int) ServerCreate {
 	if user == "" {
-		log.Error("ilo user missing, please specify with ONEVIEW_ILO_USER or --oneview-ilo-user arguments.")
+		log.Errorf("ilo user missing, please specify with ONEVIEW_ILO_USER or --oneview-ilo-user arguments.")
 	}
 	if user == "" {
-		log.Error("ilo password missing, please specify with ONEVIEW_ILO_PASSWORD or --oneview-ilo-password arguments.")
+		log.Errorf("ilo password missing, please specify with ONEVIEW_ILO_PASSWORD or --oneview-ilo-password arguments.")
 	}
 	return ServerCreate{
 		// Type:      "OSDIlo", //TODO: this causes notmal os-deployment-servers actions to fail.
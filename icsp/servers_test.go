@@ -7,7 +7,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,7 +19,7 @@ func TestGetPublicIPV4(t *testing.T) {
 		serialNumber string
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestGetPublicIPV4")
+		log.Debugf("implements acceptance test for TestGetPublicIPV4")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -39,7 +38,7 @@ func TestGetPublicIPV4(t *testing.T) {
 		// TODO: implement a test
 		// need to simplate createing public_interface custom attribute object
 		// need to read custom attribute object, see server_customattribute_test.go
-		log.Debug("implements unit test for TestGetPublicIPV4")
+		log.Debugf("implements unit test for TestGetPublicIPV4")
 	}
 }
 
@@ -55,7 +54,7 @@ func TestGetInterfaceFromMac(t *testing.T) {
 		data         Interface
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestGetInterfaceFromMac")
+		log.Debugf("implements acceptance test for TestGetInterfaceFromMac")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -79,7 +78,7 @@ func TestGetInterfaceFromMac(t *testing.T) {
 		assert.Equal(t, macAddr, data.MACAddr, "Failed to get interface -> %+v", data)
 		log.Infof("Found interface -> %+v", data)
 	} else {
-		log.Debug("implements unit test for TestGetInterfaces")
+		log.Debugf("implements unit test for TestGetInterfaces")
 		d, c = getTestDriverU()
 		jsonServerData := d.Tc.GetTestData(d.Env, "ServerJSONString").(string)
 		log.Debugf("jsonServerData => %s", jsonServerData)
@@ -102,7 +101,7 @@ func TestGetInterfaces(t *testing.T) {
 		err          error
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestGetInterfaces")
+		log.Debugf("implements acceptance test for TestGetInterfaces")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -124,7 +123,7 @@ func TestGetInterfaces(t *testing.T) {
 			log.Infof("inet ip -> %+v", inet.MACAddr)
 		}
 	} else {
-		log.Debug("implements unit test for TestGetInterfaces")
+		log.Debugf("implements unit test for TestGetInterfaces")
 		d, c = getTestDriverU()
 		jsonServerData := d.Tc.GetTestData(d.Env, "ServerJSONString").(string)
 		log.Debugf("jsonServerData => %s", jsonServerData)
@@ -156,7 +155,7 @@ func TestGetInterface(t *testing.T) {
 		data         Interface
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestGetInterfaceName")
+		log.Debugf("implements acceptance test for TestGetInterfaceName")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -179,7 +178,7 @@ func TestGetInterface(t *testing.T) {
 		assert.True(t, len(data.Slot) > 0, "Failed to get a an interface name -> %+v", data)
 		log.Infof("Interface name -> %+v", data.Slot)
 	} else {
-		log.Debug("implements unit test for TestGetInterfaceName")
+		log.Debugf("implements unit test for TestGetInterfaceName")
 		d, c = getTestDriverU()
 		jsonServerData := d.Tc.GetTestData(d.Env, "ServerJSONString").(string)
 		log.Debugf("jsonServerData => %s", jsonServerData)
@@ -201,7 +200,7 @@ func TestSaveServer(t *testing.T) {
 		c *ICSPClient
 	)
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestCreateServer")
+		log.Debugf("implements acceptance test for TestCreateServer")
 		d, c = getTestDriverA()
 		if c == nil {
 			t.Fatalf("Failed to execute getTestDriver() ")
@@ -223,7 +222,7 @@ func TestSaveServer(t *testing.T) {
 		_, testValue2 := s.GetValueItem("docker_user", "server")
 		assert.Equal(t, "docker", testValue2.Value, "Should return the saved custom attribute")
 	} else {
-		log.Debug("implements unit test for TestCreateServer")
+		log.Debugf("implements unit test for TestCreateServer")
 		var s Server
 		_, c = getTestDriverU()
 		s, err := c.SaveServer(s)
@@ -313,7 +312,7 @@ func TestGetServerBySerialNumber(t *testing.T) {
 func TestDeleteServer(t *testing.T) {
 	var c *ICSPClient
 	if os.Getenv("ICSP_TEST_ACCEPTANCE") == "true" {
-		log.Debug("implements acceptance test for TestDeleteServer")
+		log.Debugf("implements acceptance test for TestDeleteServer")
 		// check if the server exist
 		_, c = getTestDriverA()
 		if c == nil {
@@ -324,7 +323,7 @@ func TestDeleteServer(t *testing.T) {
 		assert.True(t, data)
 		assert.NoError(t, err, "DeleteServer threw error -> %s, %+v\n", err, data)
 	} else {
-		log.Debug("implements unit test for TestDeleteServer")
+		log.Debugf("implements unit test for TestDeleteServer")
 	}
 }
 
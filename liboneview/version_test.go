@@ -5,10 +5,12 @@ import (
 
 	// "github.com/docker/machine/drivers/oneview/icsp"
 	// "github.com/docker/machine/drivers/oneview/ov"
-	"github.com/docker/machine/libmachine/log"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/stretchr/testify/assert"
 )
 
+var log = rest.SharedLogger()
+
 // TestCalculateVersion
 func TestCalculateVersion(t *testing.T) {
 	var v Version
@@ -19,11 +19,18 @@ package ov
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
+// DefaultAPIVersion - X-API-Version sent when the client has no APIVersion,
+// the oldest schema this package is known to work with
+const DefaultAPIVersion = 120
+
+// apiVersionURI - answers without an X-API-Version, so never gets one added
+const apiVersionURI = "/rest/version"
+
 // APIVersion struct
 type APIVersion struct {
 	CurrentVersion int `json:"currentVersion,omitempty"`
@@ -34,7 +41,7 @@ type APIVersion struct {
 // returns structure APIVersion
 func (c *OVClient) GetAPIVersion() (APIVersion, error) {
 	var (
-		uri        = "/rest/version"
+		uri        = apiVersionURI
 		apiversion APIVersion
 	)
 
@@ -62,3 +69,35 @@ func (c *OVClient) RefreshVersion() error {
 	c.APIVersion = v.CurrentVersion
 	return nil
 }
+
+// GetMaxAPIVersion - get the newest api version the appliance supports, see
+// GetAPIVersion for the supported range
+func (c *OVClient) GetMaxAPIVersion() (int, error) {
+	v, err := c.GetAPIVersion()
+	if err != nil {
+		return 0, err
+	}
+	return v.CurrentVersion, nil
+}
+
+// getAPIVersion - the api version to send, DefaultAPIVersion when not set
+func (c *OVClient) getAPIVersion() int {
+	if c.APIVersion <= 0 {
+		return DefaultAPIVersion
+	}
+	return c.APIVersion
+}
+
+// setAPIVersionHeader - add X-API-Version to the call headers when missing,
+// without it the appliance answers with its oldest schema
+func (c *OVClient) setAPIVersionHeader() {
+	if _, ok := c.Option.Headers["X-API-Version"]; ok {
+		return
+	}
+	headers := make(map[string]string, len(c.Option.Headers)+1)
+	for k, v := range c.Option.Headers {
+		headers[k] = v
+	}
+	headers["X-API-Version"] = strconv.Itoa(c.getAPIVersion())
+	c.SetAuthHeaderOptions(headers)
+}
@@ -1,10 +1,14 @@
 package ov
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"testing"
 
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,3 +60,39 @@ func TestRefreshVersion(t *testing.T) {
 	}
 
 }
+
+// TestGetMaxAPIVersion get the newest version from a fake appliance
+func TestGetMaxAPIVersion(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/version", `{"currentVersion":300,"minimumVersion":1}`)
+
+	v, err := c.GetMaxAPIVersion()
+	assert.NoError(t, err, "GetMaxAPIVersion threw error -> %s", err)
+	assert.Equal(t, 300, v)
+	_, ok := c.Option.Headers["X-API-Version"]
+	assert.False(t, ok, "/rest/version should not get an X-API-Version")
+}
+
+// TestAPIVersionHeader X-API-Version is sent on calls that didn't set it
+func TestAPIVersionHeader(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var sent []string
+	f.Handle(rest.GET, "/rest/server-hardware/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		sent = append(sent, c.Option.Headers["X-API-Version"])
+		return []byte(`{}`), nil
+	})
+
+	c.SetAuthHeaderOptions(map[string]string{"auth": "key"})
+	_, err := c.RestAPICall(rest.GET, "/rest/server-hardware/1", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	c.APIVersion = 0
+	c.SetAuthHeaderOptions(nil)
+	_, err = c.RestAPICall(rest.GET, "/rest/server-hardware/1", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	c.SetAuthHeaderOptions(map[string]string{"X-API-Version": "200"})
+	_, err = c.RestAPICall(rest.GET, "/rest/server-hardware/1", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	assert.Equal(t, []string{"120", strconv.Itoa(DefaultAPIVersion), "200"}, sent)
+}
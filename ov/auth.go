@@ -18,12 +18,15 @@ limitations under the License.
 package ov
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // AuthHeader Marshal a json into a auth header
@@ -37,7 +40,7 @@ type AuthHeader struct {
 func (c *OVClient) GetAuthHeaderMap() map[string]string {
 	return map[string]string{
 		"Content-Type":  "application/json; charset=utf-8",
-		"X-API-Version": strconv.Itoa(c.APIVersion),
+		"X-API-Version": strconv.Itoa(c.getAPIVersion()),
 		"auth":          c.APIKey,
 	}
 }
@@ -90,11 +93,104 @@ func (c *OVClient) RefreshLogin() error {
 	return nil
 }
 
+var (
+	// ErrApplianceUnreachable - the appliance didn't answer a Ping
+	ErrApplianceUnreachable = errors.New("Appliance is unreachable")
+	// ErrSessionInvalid - the appliance answered a Ping but the login or session failed
+	ErrSessionInvalid = errors.New("Appliance session is not valid")
+)
+
+// Ping - preflight check that the appliance answers and the credentials get a
+// working session, the error wraps ErrApplianceUnreachable or ErrSessionInvalid
+func (c *OVClient) Ping() error {
+	if _, err := c.GetAPIVersion(); err != nil {
+		return fmt.Errorf("%w, %s: %s", ErrApplianceUnreachable, c.Endpoint, err)
+	}
+	if err := c.RefreshLogin(); err != nil {
+		return fmt.Errorf("%w, login to %s as %s: %s", ErrSessionInvalid, c.Endpoint, c.User, err)
+	}
+	if _, err := c.GetIdleTimeout(); err != nil {
+		return fmt.Errorf("%w, %s: %s", ErrSessionInvalid, c.Endpoint, err)
+	}
+	return nil
+}
+
+// loginSessionsURI - calls on it never re-login, a 401 means bad credentials
+const loginSessionsURI = "/rest/login-sessions"
+
+// RestAPICall - rest call that logs in again and retries once when the session
+// expired mid operation, unless DisableSessionRefresh is set, X-API-Version
+// is always sent
+func (c *OVClient) RestAPICall(method rest.Method, path string, options interface{}) ([]byte, error) {
+	return c.RestAPICallContext(context.Background(), method, path, options)
+}
+
+// RestAPICallContext - RestAPICall bound to ctx, the retry after a refresh
+// carries the same correlation id
+func (c *OVClient) RestAPICallContext(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	var data []byte
+	err := c.withSession(ctx, path, func(ctx context.Context) (err error) {
+		data, err = c.Client.RestAPICallContext(ctx, method, path, options)
+		return err
+	})
+	return data, err
+}
+
+// RestAPICallDecode - RestAPICallContext decoding the response into v as it is
+// read, for large responses, see rest.Client RestAPICallDecode
+func (c *OVClient) RestAPICallDecode(ctx context.Context, method rest.Method, path string, options interface{}, v interface{}) error {
+	return c.withSession(ctx, path, func(ctx context.Context) error {
+		return c.Client.RestAPICallDecode(ctx, method, path, options, v)
+	})
+}
+
+// withSession - make call with the X-API-Version header, logging in again and
+// calling once more when the session expired, unless DisableSessionRefresh is set
+func (c *OVClient) withSession(ctx context.Context, path string, call func(ctx context.Context) error) error {
+	ctx = c.CorrelationContext(ctx)
+	if path != apiVersionURI {
+		c.setAPIVersionHeader()
+	}
+	err := call(ctx)
+	var serr *rest.StatusError
+	if err == nil || c.DisableSessionRefresh || path == loginSessionsURI ||
+		!errors.As(err, &serr) || serr.StatusCode != http.StatusUnauthorized {
+		return err
+	}
+	log.Debugf("[%s] Session expired calling %s, logging in again", rest.CorrelationID(ctx), path)
+	if lerr := c.refreshSession(); lerr != nil {
+		log.Warnf("[%s] Unable to refresh session for %s -> %s", rest.CorrelationID(ctx), path, lerr)
+		return err
+	}
+	return call(ctx)
+}
+
+// refreshSession - get a new session ID and put it in the headers of the
+// call being retried
+func (c *OVClient) refreshSession() error {
+	headers := c.Option.Headers
+	s, err := c.SessionLogin()
+	c.SetAuthHeaderOptions(headers)
+	if err != nil {
+		return err
+	}
+	c.APIKey = s.ID
+	updated := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if k == "auth" || k == "Session-ID" {
+			v = s.ID
+		}
+		updated[k] = v
+	}
+	c.SetAuthHeaderOptions(updated)
+	return nil
+}
+
 // SessionLogin Login to OneView and get a session ID
 // returns Session structure
 func (c *OVClient) SessionLogin() (Session, error) {
 	var (
-		uri     = "/rest/login-sessions"
+		uri     = loginSessionsURI
 		body    = Auth{UserName: c.User, Password: c.Password, Domain: c.Domain}
 		session Session
 	)
@@ -105,7 +201,7 @@ func (c *OVClient) SessionLogin() (Session, error) {
 		return session, err
 	}
 
-	log.Debugf("SessionLogin %s", data)
+	log.Debugf("SessionLogin %s", rest.Redact(data))
 	if err := json.Unmarshal([]byte(data), &session); err != nil {
 		return session, err
 	}
@@ -117,9 +213,9 @@ func (c *OVClient) SessionLogin() (Session, error) {
 // returns Session structure
 func (c *OVClient) SessionLogout() error {
 	var (
-		uri = "/rest/login-sessions"
+		uri = loginSessionsURI
 	)
-	log.Debugf("Calling logout for header -> %+v", c.GetAuthHeaderMap())
+	log.Debugf("Calling logout for header -> %+v", rest.RedactHeaders(c.GetAuthHeaderMap()))
 	if c.APIKey == "none" {
 		log.Debugf("already logged out")
 		return nil
@@ -134,6 +230,32 @@ func (c *OVClient) SessionLogout() error {
 	return nil
 }
 
+// Logout - end the session on the appliance and clear the session id, a session
+// that already expired counts as logged out, clones sharing the session lose it
+// too, a call made afterwards logs in again
+func (c *OVClient) Logout() error {
+	if strings.TrimSpace(c.APIKey) == "" || c.APIKey == "none" {
+		c.APIKey = "none"
+		return nil
+	}
+	err := c.SessionLogout()
+	var serr *rest.StatusError
+	if errors.As(err, &serr) && (serr.StatusCode == http.StatusUnauthorized || serr.StatusCode == http.StatusNotFound) {
+		err = nil
+	}
+	c.APIKey = "none"
+	c.SetAuthHeaderOptions(nil)
+	return err
+}
+
+// Close - Logout and close the idle connections to the appliance, call it when
+// done with a client to free its session slot
+func (c *OVClient) Close() error {
+	err := c.Logout()
+	c.CloseIdleConnections()
+	return err
+}
+
 // GetIdleTimeout gets the current timeout for the logged on session
 // returns timeout in milliseconds, or error when it fails
 func (c *OVClient) GetIdleTimeout() (int64, error) {
@@ -142,7 +264,7 @@ func (c *OVClient) GetIdleTimeout() (int64, error) {
 		timeout TimeOut
 		header  map[string]string
 	)
-	log.Debugf("Calling idel-timeout get for header -> %+v", c.GetAuthHeaderMap())
+	log.Debugf("Calling idel-timeout get for header -> %+v", rest.RedactHeaders(c.GetAuthHeaderMap()))
 	header = c.GetAuthHeaderMap()
 	header["Session-ID"] = header["auth"]
 	c.SetAuthHeaderOptions(header)
@@ -165,7 +287,7 @@ func (c *OVClient) SetIdleTimeout(thetime int64) error {
 		header  map[string]string
 	)
 	timeout.IdleTimeout = thetime
-	log.Debugf("Calling idel-timeout POST for header -> %+v", c.GetAuthHeaderMap())
+	log.Debugf("Calling idel-timeout POST for header -> %+v", rest.RedactHeaders(c.GetAuthHeaderMap()))
 	header = c.GetAuthHeaderMap()
 	header["Session-ID"] = header["auth"]
 	c.SetAuthHeaderOptions(header)
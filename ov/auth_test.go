@@ -1,12 +1,16 @@
 package ov
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 	//"time"
 
-	"github.com/docker/machine/libmachine/log"
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -162,3 +166,85 @@ func TestSessionExpiredKey(t *testing.T) {
 
 }
 */
+
+// TestSessionRefreshOn401 - an expired session is replaced and the call retried once
+func TestSessionRefreshOn401(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	c.APIKey = "expired"
+	f.Handle(rest.GET, "/rest/version", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		if c.Option.Headers["auth"] != "ovtest-session" {
+			return nil, ovtest.StatusError(http.StatusUnauthorized, "session expired")
+		}
+		return []byte(`{"currentVersion":120}`), nil
+	})
+
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	data, err := c.RestAPICall(rest.GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	assert.Equal(t, `{"currentVersion":120}`, string(data))
+	assert.Equal(t, "ovtest-session", c.APIKey)
+	assert.Equal(t, "120", c.Option.Headers["X-API-Version"])
+
+	// disabled, the 401 is returned as is
+	c.APIKey = "expired"
+	c.DisableSessionRefresh = true
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	_, err = c.RestAPICall(rest.GET, "/rest/version", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "expired", c.APIKey)
+
+	// bad credentials, login is tried once and the original error returned
+	c.DisableSessionRefresh = false
+	f.HandleStatus(rest.POST, "/rest/login-sessions", http.StatusUnauthorized, "bad credentials")
+	before := len(f.Calls())
+	_, err = c.RestAPICall(rest.GET, "/rest/version", nil)
+	assert.Contains(t, err.Error(), "session expired")
+	assert.Equal(t, 2, len(f.Calls())-before)
+}
+
+// TestPing - the preflight says whether the appliance or the session failed
+func TestPing(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	assert.True(t, errors.Is(c.Ping(), ErrApplianceUnreachable))
+
+	f.HandleJSON(rest.GET, "/rest/version", `{"currentVersion":120,"minimumVersion":1}`)
+	assert.NoError(t, c.Ping())
+	assert.Equal(t, "ovtest-session", c.APIKey)
+
+	c.APIKey = "none"
+	f.HandleStatus(rest.POST, "/rest/login-sessions", http.StatusUnauthorized, "bad credentials")
+	err := c.Ping()
+	assert.True(t, errors.Is(err, ErrSessionInvalid), "expected session invalid, got %s", err)
+	assert.Contains(t, err.Error(), "bad credentials")
+}
+
+// TestLogout - the session is deleted on the appliance once, an expired one counts as logged out
+func TestLogout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	assert.NoError(t, c.RefreshLogin())
+	assert.Equal(t, "ovtest-session", c.APIKey)
+
+	assert.NoError(t, c.Close())
+	last := f.Calls()[len(f.Calls())-1]
+	assert.Equal(t, rest.DELETE, last.Method)
+	assert.Equal(t, "/rest/login-sessions", last.Path)
+	assert.Equal(t, "none", c.APIKey)
+	assert.Empty(t, c.Option.Headers["auth"])
+
+	before := len(f.Calls())
+	assert.NoError(t, c.Logout())
+	assert.Equal(t, before, len(f.Calls()), "already logged out")
+
+	c.APIKey = "expired"
+	f.HandleStatus(rest.DELETE, "/rest/login-sessions", http.StatusUnauthorized, "session expired")
+	assert.NoError(t, c.Logout())
+	assert.Equal(t, "none", c.APIKey)
+
+	c.APIKey = "ovtest-session"
+	f.HandleStatus(rest.DELETE, "/rest/login-sessions", http.StatusInternalServerError, "down")
+	assert.Error(t, c.Logout())
+	assert.Equal(t, "none", c.APIKey)
+}
@@ -0,0 +1,55 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ErrEFuseNotSupported - the server hardware isn't in an enclosure bay, only
+// blades can be e-fuse reset
+var ErrEFuseNotSupported = errors.New("E-Fuse reset is only supported for blades in an enclosure")
+
+// eFuse - bayPowerState value removing and restoring all power of a device bay
+const eFuse = "E-Fuse"
+
+// EFuseReset - momentarily remove all power, auxiliary power included, from the
+// enclosure bay of the blade at uri and wait for the task. The blade and its
+// management processor restart, running workloads are lost and the blade is off
+// once done. A last resort recovery for an unresponsive management processor,
+// unlike ColdBoot it doesn't go through the management processor.
+func (c *OVClient) EFuseReset(uri utils.Nstring, opts ...TaskOption) (*Task, error) {
+	hardware, err := c.GetServerHardware(uri)
+	if err != nil {
+		return nil, err
+	}
+	if hardware.URI.IsNil() {
+		return nil, ErrNoBladeHardware
+	}
+	enclosure, ok := hardware.GetEnclosureURI()
+	if !ok || hardware.GetBay() < 1 {
+		return nil, fmt.Errorf("%w, %s", ErrEFuseNotSupported, hardware.Name)
+	}
+	log.Warnf("E-Fuse reset of server %s, %s in bay %d of %s.", hardware.Name, hardware.SerialNumber, hardware.GetBay(), enclosure)
+	path := fmt.Sprintf("/deviceBays/%d/bayPowerState", hardware.GetBay())
+	body := []PatchRequest{{Op: "replace", Path: path, Value: eFuse}}
+	return c.patchResource(enclosure, body, "e-fuse reset", opts...)
+}
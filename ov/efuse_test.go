@@ -0,0 +1,29 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// e-fuse test, the enclosure bay of the blade is patched and the task waited on
+func TestEFuseReset(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	e := f.AddEnclosure("/rest/enclosures/enc1", "enc1", 3)
+	b := e.AddBlade(f, "/rest/server-hardware/2", 2, "On")
+	f.AddBlade("/rest/server-hardware/rack", "rack server", "On")
+
+	task, err := c.EFuseReset("/rest/server-hardware/2", TaskWaitTime(0))
+	assert.NoError(t, err, "EFuseReset threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	assert.Equal(t, []string{"2:E-Fuse"}, e.BayResets())
+	assert.Equal(t, "Off", b.GetState())
+	assert.Empty(t, b.PowerRequests(), "not a power state request")
+
+	_, err = c.EFuseReset("/rest/server-hardware/rack")
+	assert.True(t, errors.Is(err, ErrEFuseNotSupported), "expected not supported, got %s", err)
+	assert.Len(t, e.BayResets(), 1)
+}
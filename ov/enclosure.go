@@ -0,0 +1,144 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ErrNoEnclosure - the enclosure uri to get is missing or isn't an enclosure
+var ErrNoEnclosure = errors.New("Error enclosure uri is required")
+
+// DeviceBay - a device bay of an enclosure
+type DeviceBay struct {
+	BayNumber      int           `json:"bayNumber,omitempty"`      // "bayNumber": 1,
+	DevicePresence string        `json:"devicePresence,omitempty"` // "devicePresence": "Present",
+	DeviceURI      utils.Nstring `json:"deviceUri,omitempty"`      // "deviceUri": "/rest/server-hardware/30373237-3132-4D32-3235-303930524D57",
+	ProfileURI     utils.Nstring `json:"profileUri,omitempty"`     // "profileUri": null,
+	Model          string        `json:"model,omitempty"`          // "model": "ProLiant BL460c Gen9",
+}
+
+// Enclosure - the enclosure fields used for power, see the appliance api for the
+// rest of the resource
+type Enclosure struct {
+	Type                string        `json:"type,omitempty"`                // "type": "EnclosureV200",
+	Name                string        `json:"name,omitempty"`                // "name": "Encl1",
+	SerialNumber        string        `json:"serialNumber,omitempty"`        // "serialNumber": "092SN51207RR",
+	EnclosureType       string        `json:"enclosureType,omitempty"`       // "enclosureType": "BladeSystem c7000 Enclosure G2",
+	PowerMode           string        `json:"powerMode,omitempty"`           // "powerMode": "RedundantPowerFeed",
+	PowerCapacityWatts  int           `json:"powerCapacityWatts,omitempty"`  // "powerCapacityWatts": 14400,
+	PowerAllocatedWatts int           `json:"powerAllocatedWatts,omitempty"` // "powerAllocatedWatts": 4285,
+	PowerAvailableWatts int           `json:"powerAvailableWatts,omitempty"` // "powerAvailableWatts": 10115,
+	DeviceBayCount      int           `json:"deviceBayCount,omitempty"`      // "deviceBayCount": 16,
+	DeviceBays          []DeviceBay   `json:"deviceBays,omitempty"`          // "deviceBays": [],
+	Status              string        `json:"status,omitempty"`              // "status": "OK",
+	URI                 utils.Nstring `json:"uri,omitempty"`                 // "uri": "/rest/enclosures/092SN51207RR"
+}
+
+// BayPower - power of a device bay in an enclosure power summary
+type BayPower struct {
+	Bay        int
+	Present    bool          // a device is in the bay
+	DeviceURI  utils.Nstring // server hardware in the bay, empty when unknown
+	Name       string        // server hardware name, "Encl1, bay 1"
+	PowerState PowerState    // P_UNKNOWN when the bay has no server hardware
+}
+
+// EnclosurePowerSummary - power capacity of an enclosure and the power state of its bays
+type EnclosurePowerSummary struct {
+	URI            utils.Nstring
+	Name           string
+	PowerMode      string
+	CapacityWatts  int // power present in the enclosure
+	AllocatedWatts int // power allocated to the devices
+	AvailableWatts int // power left to allocate
+	Bays           []BayPower
+}
+
+// HasHeadroom - true when the enclosure has watts left to allocate
+func (s EnclosurePowerSummary) HasHeadroom(watts int) bool {
+	return s.AvailableWatts >= watts
+}
+
+// GetEnclosure - get the enclosure at uri
+func (c *OVClient) GetEnclosure(uri utils.Nstring) (Enclosure, error) {
+	var enclosure Enclosure
+	if uri.IsNil() || !strings.HasPrefix(string(uri), "/rest/enclosures/") {
+		return enclosure, ErrNoEnclosure
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+
+	// rest call
+	data, err := c.RestAPICall(rest.GET, uri.String(), nil)
+	if err != nil {
+		return enclosure, err
+	}
+
+	log.Debugf("GetEnclosure %s", data)
+	if err := json.Unmarshal([]byte(data), &enclosure); err != nil {
+		return enclosure, err
+	}
+	return enclosure, nil
+}
+
+// GetEnclosurePowerSummary - get the power capacity of the enclosure at uri and
+// the power state of the server hardware in each of its bays
+func (c *OVClient) GetEnclosurePowerSummary(uri utils.Nstring) (EnclosurePowerSummary, error) {
+	enclosure, err := c.GetEnclosure(uri)
+	if err != nil {
+		return EnclosurePowerSummary{}, err
+	}
+	blades, err := c.GetServerHardwareByEnclosure(string(uri))
+	if err != nil {
+		return EnclosurePowerSummary{}, err
+	}
+	bybay := make(map[int]ServerHardware, len(blades))
+	for _, b := range blades {
+		bybay[b.GetBay()] = b
+	}
+
+	summary := EnclosurePowerSummary{
+		URI:            enclosure.URI,
+		Name:           enclosure.Name,
+		PowerMode:      enclosure.PowerMode,
+		CapacityWatts:  enclosure.PowerCapacityWatts,
+		AllocatedWatts: enclosure.PowerAllocatedWatts,
+		AvailableWatts: enclosure.PowerAvailableWatts,
+	}
+	for _, bay := range enclosure.DeviceBays {
+		power := BayPower{
+			Bay:        bay.BayNumber,
+			Present:    strings.EqualFold(bay.DevicePresence, "Present"),
+			DeviceURI:  bay.DeviceURI,
+			PowerState: P_UNKNOWN,
+		}
+		if b, ok := bybay[bay.BayNumber]; ok {
+			power.DeviceURI, power.Name = b.URI, b.Name
+			power.PowerState, _ = ParsePowerState(b.PowerState)
+		}
+		summary.Bays = append(summary.Bays, power)
+	}
+	return summary, nil
+}
@@ -0,0 +1,38 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetEnclosurePowerSummary verify capacity and bay power states are combined
+func TestGetEnclosurePowerSummary(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	e := f.AddEnclosure("/rest/enclosures/092SN51207RR", "Encl1", 3)
+	e.CapacityWatts, e.AllocatedWatts = 14400, 4285
+	e.AddBlade(f, "/rest/server-hardware/3", 3, "Off")
+	e.AddBlade(f, "/rest/server-hardware/1", 1, "On")
+	f.AddBlade("/rest/server-hardware/rack", "rack1", "On")
+
+	summary, err := c.GetEnclosurePowerSummary("/rest/enclosures/092SN51207RR")
+	assert.NoError(t, err, "GetEnclosurePowerSummary threw error -> %s", err)
+	assert.Equal(t, "Encl1", summary.Name)
+	assert.Equal(t, "RedundantPowerFeed", summary.PowerMode)
+	assert.Equal(t, 14400, summary.CapacityWatts)
+	assert.Equal(t, 4285, summary.AllocatedWatts)
+	assert.Equal(t, 10115, summary.AvailableWatts)
+	assert.True(t, summary.HasHeadroom(500))
+	assert.False(t, summary.HasHeadroom(20000))
+	assert.Equal(t, []BayPower{
+		{Bay: 1, Present: true, DeviceURI: "/rest/server-hardware/1", Name: "Encl1, bay 1", PowerState: P_ON},
+		{Bay: 2, PowerState: P_UNKNOWN},
+		{Bay: 3, Present: true, DeviceURI: "/rest/server-hardware/3", Name: "Encl1, bay 3", PowerState: P_OFF},
+	}, summary.Bays)
+
+	_, err = c.GetEnclosurePowerSummary("/rest/server-hardware/1")
+	assert.True(t, errors.Is(err, ErrNoEnclosure))
+}
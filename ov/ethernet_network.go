@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 type EthernetNetwork struct {
@@ -140,7 +139,7 @@ func (c *OVClient) DeleteEthernetNetwork(name string) error {
 		log.Debugf("task -> %+v", t)
 		uri = eNet.URI.String()
 		if uri == "" {
-			log.Warn("Unable to post delete, no uri found.")
+			log.Warnf("Unable to post delete, no uri found.")
 			t.TaskIsDone = true
 			return err
 		}
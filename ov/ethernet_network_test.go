@@ -2,7 +2,6 @@ package ov
 
 import (
 	"fmt"
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
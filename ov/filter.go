@@ -0,0 +1,26 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import "strings"
+
+// filterValue - quote v for a filter query, single quotes in v are doubled so a
+// name like "O'Brien" stays one value
+func filterValue(v string) string {
+	return "'" + strings.Replace(v, "'", "''", -1) + "'"
+}
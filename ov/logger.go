@@ -0,0 +1,55 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"io"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// Logger - logging interface used by the ov package, see rest.Logger
+type Logger interface {
+	rest.Logger
+}
+
+// log is the Logger used by the ov package, the one set with SetLogger
+var log Logger = rest.SharedLogger()
+
+// SetLogger - set the Logger used by the ov, icsp and rest packages, nil discards
+// all logs, safe to call while other goroutines are logging
+func SetLogger(l Logger) {
+	rest.SetLogger(l)
+}
+
+// NewJSONLogger - get a Logger writing one json object per line to w, power
+// operations add the blade, serial number, task uri and percent as keys,
+// select it with SetLogger(NewJSONLogger(os.Stdout))
+func NewJSONLogger(w io.Writer) *rest.JSONLogger {
+	return rest.NewJSONLogger(w)
+}
+
+// logWith - the package Logger adding fields as structured keys, when it is
+// a rest.FieldLogger, as is otherwise
+func logWith(fields rest.Fields) Logger {
+	l := rest.GetLogger()
+	if fl, ok := l.(rest.FieldLogger); ok {
+		return fl.WithFields(fields)
+	}
+	return l
+}
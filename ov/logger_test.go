@@ -0,0 +1,72 @@
+package ov
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetLogger verify the ov and rest packages share the injected logger
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(rest.GetLogger())
+	var out bytes.Buffer
+	SetLogger(&rest.StdLogger{Out: &out, Err: &out})
+	log.Warnf("blade %s", "se05, bay 16")
+	rest.GetLogger().Infof("rest %s", "call")
+	assert.Equal(t, "blade se05, bay 16\nrest call\n", out.String())
+}
+
+// lockedBuffer - buffer safe for the goroutines of a power operation
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestJSONLoggerPowerFields verify power operations log the blade serial, task uri
+// and percent as json keys
+func TestJSONLoggerPowerFields(t *testing.T) {
+	defer SetLogger(rest.GetLogger())
+	var out lockedBuffer
+	SetLogger(NewJSONLogger(&out))
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off").TaskPolls = 1
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+
+	var progress map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		if _, ok := entry["percent"]; ok && progress == nil {
+			progress = entry
+		}
+	}
+	assert.NotNil(t, progress, "no progress line in %s", out.String())
+	assert.Equal(t, "OVTESTenc1, bay 1", progress["serial"])
+	assert.Equal(t, "enc1, bay 1", progress["blade"])
+	assert.Equal(t, "On", progress["state"])
+	assert.NotEmpty(t, progress["task"])
+	assert.NotEmpty(t, progress["correlation"])
+}
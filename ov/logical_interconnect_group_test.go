@@ -3,7 +3,6 @@ package ov
 import (
 	"fmt"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 //TODO change this struct to hold the variables from the GET API response body variables
@@ -145,7 +144,7 @@ func (c *OVClient) DeleteLogicalSwitchGroup(name string) error {
 		log.Debugf("task -> %+v", t)
 		uri = logicalSwitchGroup.URI.String()
 		if uri == "" {
-			log.Warn("Unable to post delete, no uri found.")
+			log.Warnf("Unable to post delete, no uri found.")
 			t.TaskIsDone = true
 			return err
 		}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 type NetworkSet struct {
@@ -135,7 +134,7 @@ func (c *OVClient) DeleteNetworkSet(name string) error {
 		log.Debugf("task -> %+v", t)
 		uri = netSet.URI.String()
 		if uri == "" {
-			log.Warn("Unable to post delete, no uri found.")
+			log.Warnf("Unable to post delete, no uri found.")
 			t.TaskIsDone = true
 			return err
 		}
@@ -0,0 +1,77 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ov
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// metric names reported by the ov package, see rest.MetricRestCalls for the rest calls
+const (
+	MetricPowerRequests = "oneview_power_requests_total"
+	MetricPowerPolls    = "oneview_power_task_polls_total"
+	MetricPowerTimeouts = "oneview_power_timeouts_total"
+	MetricPowerDuration = "oneview_power_operation_duration_seconds"
+)
+
+// Observer - metrics interface used by the ov package, see rest.Observer
+type Observer interface {
+	rest.Observer
+}
+
+// observer is the Observer used by the ov package
+var observer Observer = rest.GetObserver()
+
+// SetObserver - set the Observer used by the ov and rest packages, nil drops all metrics
+func SetObserver(o Observer) {
+	rest.SetObserver(o)
+	observer = rest.GetObserver()
+}
+
+// observePowerSubmission - count a power request by desired state and result,
+// "submitted", "unchanged" when already in the state, or "error"
+func observePowerSubmission(s PowerState, sub powerSubmission) {
+	result := "unchanged"
+	if sub.Err != nil {
+		result = "error"
+	} else if sub.URI != "" {
+		result = "submitted"
+	}
+	observer.IncCounter(MetricPowerRequests, map[string]string{"state": s.APIValue(), "result": result})
+}
+
+// observePowerOperation - report the duration of a power operation by desired state
+// and result, "ok", "timeout", "cancelled" or "error", timeouts are counted too
+func observePowerOperation(s PowerState, start time.Time, err error) {
+	var result string
+	switch {
+	case err == nil:
+		result = "ok"
+	case errors.Is(err, ErrPowerTimeout):
+		result = "timeout"
+		observer.IncCounter(MetricPowerTimeouts, map[string]string{"state": s.APIValue()})
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		result = "cancelled"
+	default:
+		result = "error"
+	}
+	observer.ObserveDuration(MetricPowerDuration, time.Since(start), map[string]string{"state": s.APIValue(), "result": result})
+}
@@ -0,0 +1,64 @@
+package ov
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordObserver - Observer keeping the counters and durations it was given
+type recordObserver struct {
+	mu        sync.Mutex
+	counters  map[string]int
+	durations map[string]int
+}
+
+func newRecordObserver() *recordObserver {
+	return &recordObserver{counters: map[string]int{}, durations: map[string]int{}}
+}
+
+func (o *recordObserver) IncCounter(name string, labels map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.counters[name+" "+labels["state"]+" "+labels["result"]]++
+}
+
+func (o *recordObserver) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.durations[name+" "+labels["state"]+" "+labels["result"]]++
+}
+
+// TestSetObserver verify power requests, polls and their outcome are observed
+func TestSetObserver(t *testing.T) {
+	defer SetObserver(observer)
+	o := newRecordObserver()
+	SetObserver(o)
+	assert.Equal(t, o, rest.GetObserver())
+
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+
+	assert.Equal(t, 1, o.counters[MetricPowerRequests+" On submitted"])
+	assert.Equal(t, 1, o.counters[MetricPowerRequests+" On unchanged"])
+	assert.True(t, o.counters[MetricPowerPolls+" On "] > 0)
+	assert.Equal(t, 2, o.durations[MetricPowerDuration+" On ok"])
+	assert.True(t, o.counters[rest.MetricRestCalls+"  "] > 0)
+
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerExecutor(P_UNKNOWN)
+	assert.Error(t, err)
+	assert.Equal(t, 1, o.counters[MetricPowerRequests+" Unknown error"])
+	assert.Equal(t, 1, o.durations[MetricPowerDuration+" Unknown error"])
+}
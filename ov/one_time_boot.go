@@ -0,0 +1,139 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ov
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// BootDevice - device the server hardware boots from once on its next power on
+type BootDevice int
+
+const (
+	BOOT_NORMAL BootDevice = 1 + iota
+	BOOT_PXE
+	BOOT_HDD
+	BOOT_CD
+	BOOT_USB
+)
+
+var bootdevices = [...]string{
+	"Normal",  // Normal  - no override, the configured boot order is used
+	"Network", // Network - PXE boot from the network
+	"HDD",     // HDD     - boot from the local disk
+	"CD",      // CD      - boot from the cd or dvd drive, virtual media included
+	"USB",     // USB     - boot from a usb device
+}
+
+// bootaliases - other names accepted for the boot devices
+var bootaliases = map[string]BootDevice{
+	"pxe":      BOOT_PXE,
+	"harddisk": BOOT_HDD,
+	"dvd":      BOOT_CD,
+}
+
+func (b BootDevice) String() string {
+	if b < 1 || int(b) > len(bootdevices) {
+		return ""
+	}
+	return bootdevices[b-1]
+}
+
+// Equal - case insensitive match of s, ignoring surrounding whitespace
+func (b BootDevice) Equal(s string) bool {
+	return b.String() != "" && strings.EqualFold(strings.TrimSpace(s), b.String())
+}
+
+var (
+	// ErrUnknownBootDevice - the one time boot device isn't recognized
+	ErrUnknownBootDevice = errors.New("Un-known boot device")
+	// ErrOneTimeBootPoweredOn - a one time boot needs the blade off, it only
+	// applies on the next power on
+	ErrOneTimeBootPoweredOn = errors.New("Can't power on to a one time boot device, blade isn't off")
+)
+
+// ParseBootDevice - get the BootDevice for s, PXE, HDD, CD, USB and Normal, or
+// the Network, HardDisk and DVD aliases
+func ParseBootDevice(s string) (BootDevice, error) {
+	for i := range bootdevices {
+		if b := BootDevice(i + 1); b.Equal(s) {
+			return b, nil
+		}
+	}
+	if b, ok := bootaliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return b, nil
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownBootDevice, s)
+}
+
+// SetOneTimeBoot - set the device the server hardware at uri boots from on its
+// next power on and wait for the task
+func (c *OVClient) SetOneTimeBoot(uri utils.Nstring, device BootDevice, opts ...TaskOption) (*Task, error) {
+	if device.String() == "" {
+		return nil, fmt.Errorf("%w %d", ErrUnknownBootDevice, int(device))
+	}
+	hardware, err := c.GetServerHardware(uri)
+	if err != nil {
+		return nil, err
+	}
+	if hardware.URI.IsNil() {
+		return nil, ErrNoBladeHardware
+	}
+	return c.setOneTimeBoot(hardware, device, opts...)
+}
+
+func (c *OVClient) setOneTimeBoot(hardware ServerHardware, device BootDevice, opts ...TaskOption) (*Task, error) {
+	log.Infof("Setting one time boot %s for server %s, %s.", device, hardware.Name, hardware.SerialNumber)
+	body := []PatchRequest{{Op: "replace", Path: "/oneTimeBoot", Value: device.String()}}
+	return c.patchResource(hardware.URI, body, "one time boot", opts...)
+}
+
+// PowerOnWithOneTimeBoot - power on the blade at uri booting once from device,
+// PXE, HDD or CD for example, the boot target is set while the blade is off and
+// the power on starts once that task is done. Fails with ErrOneTimeBootPoweredOn
+// without changing anything when the blade isn't off.
+func (c *OVClient) PowerOnWithOneTimeBoot(uri utils.Nstring, device string, opts ...PowerTaskOption) (PowerState, error) {
+	boot, err := ParseBootDevice(device)
+	if err != nil {
+		return P_UNKNOWN, err
+	}
+	hardware, err := c.GetServerHardware(uri)
+	if err != nil {
+		return P_UNKNOWN, err
+	}
+	if hardware.URI.IsNil() {
+		return P_UNKNOWN, ErrNoBladeHardware
+	}
+	state, err := ParsePowerState(hardware.PowerState)
+	if err != nil {
+		return P_UNKNOWN, err
+	}
+	if state != P_OFF {
+		return state, fmt.Errorf("%w, %s is %s", ErrOneTimeBootPoweredOn, hardware.Name, state)
+	}
+	var pt *PowerTask
+	pt = pt.NewPowerTask(hardware, opts...)
+	// the boot task is checked as often as the power task
+	if _, err := c.setOneTimeBoot(hardware, boot, TaskWaitTime(pt.WaitTime)); err != nil {
+		return state, err
+	}
+	return pt.PowerOn()
+}
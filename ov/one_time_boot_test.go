@@ -0,0 +1,63 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// boot device string and parse test, aliases included
+func TestParseBootDevice(t *testing.T) {
+	for _, b := range []BootDevice{BOOT_NORMAL, BOOT_PXE, BOOT_HDD, BOOT_CD, BOOT_USB} {
+		p, err := ParseBootDevice(" " + b.String() + " ")
+		assert.NoError(t, err, "ParseBootDevice threw error -> %s", err)
+		assert.Equal(t, b, p)
+	}
+	for s, b := range map[string]BootDevice{"pxe": BOOT_PXE, "PXE": BOOT_PXE, "HardDisk": BOOT_HDD, "hdd": BOOT_HDD, "cd": BOOT_CD} {
+		p, err := ParseBootDevice(s)
+		assert.NoError(t, err, "ParseBootDevice threw error -> %s", err)
+		assert.Equal(t, b, p, s)
+	}
+	_, err := ParseBootDevice("Floppy")
+	assert.True(t, errors.Is(err, ErrUnknownBootDevice))
+}
+
+// power on with a one time boot, the boot device is patched before the power request
+func TestPowerOnWithOneTimeBoot(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+
+	state, err := c.PowerOnWithOneTimeBoot("/rest/server-hardware/1", "PXE", WithWaitTime(0))
+	assert.NoError(t, err, "PowerOnWithOneTimeBoot threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	hw, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	assert.Equal(t, "Network", hw.OneTimeBoot)
+	assert.Equal(t, "On", hw.PowerState)
+
+	var order []rest.Method
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware/1" && call.Method == rest.PATCH {
+			order = append(order, call.Method)
+		}
+		if call.Path == "/rest/server-hardware/1/powerState" {
+			order = append(order, call.Method)
+		}
+	}
+	assert.Equal(t, []rest.Method{rest.PATCH, rest.PUT}, order, "boot device set before the power on")
+
+	// the blade is on now, nothing is changed
+	before := len(f.Calls())
+	_, err = c.PowerOnWithOneTimeBoot("/rest/server-hardware/1", "CD")
+	assert.True(t, errors.Is(err, ErrOneTimeBootPoweredOn), "expected powered on, got %s", err)
+	for _, call := range f.Calls()[before:] {
+		assert.Equal(t, rest.GET, call.Method)
+	}
+
+	_, err = c.PowerOnWithOneTimeBoot("/rest/server-hardware/1", "Floppy")
+	assert.True(t, errors.Is(err, ErrUnknownBootDevice))
+}
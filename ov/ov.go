@@ -22,17 +22,21 @@ import (
 	"fmt"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
-// OVClient - wrapper class for ov api's
+// OVClient - wrapper class for ov api's, not safe for concurrent use, see rest.Client
 type OVClient struct {
 	rest.Client
 }
 
-// new Client
-func (c *OVClient) NewOVClient(user string, password string, domain string, endpoint string, sslverify bool, apiversion int) *OVClient {
-	return &OVClient{
+// Clone - copy of the client for another goroutine, see rest.Client.Clone
+func (c *OVClient) Clone() *OVClient {
+	return &OVClient{*c.Client.Clone()}
+}
+
+// new Client, opts configure the rest client, for example rest.WithHTTPClient
+func (c *OVClient) NewOVClient(user string, password string, domain string, endpoint string, sslverify bool, apiversion int, opts ...rest.ClientOption) *OVClient {
+	c = &OVClient{
 		rest.Client{
 			User:       user,
 			Password:   password,
@@ -43,6 +47,8 @@ func (c *OVClient) NewOVClient(user string, password string, domain string, endp
 			APIKey:     "none",
 		},
 	}
+	c.ApplyOptions(opts...)
+	return c
 }
 
 // Create machine
@@ -66,7 +72,7 @@ func (c *OVClient) CreateMachine(host_name string, server_template string) (err
 			return err
 		}
 		pt = pt.NewPowerTask(blade)
-		if err = pt.PowerExecutor(P_OFF); err != nil {
+		if _, err = pt.PowerExecutor(P_OFF); err != nil {
 			log.Errorf("Unable to power off blade, %s, Error: %s", blade.Name, err)
 			return err
 		}
@@ -99,11 +105,11 @@ func (c *OVClient) CreateMachine(host_name string, server_template string) (err
 	}
 
 	log.Debugf("*** Blade => %+v", blade)
-	log.Debugf("client 3 *******---> %+v", blade.Client.APIKey)
+	log.Debugf("client 3 *******---> %+v", c.APIKey)
 	// now we have a server_hardware object...
 	// Power off the blade, so we can provision the server
 	pt = pt.NewPowerTask(blade)
-	if err = pt.PowerExecutor(P_OFF); err != nil {
+	if _, err = pt.PowerExecutor(P_OFF); err != nil {
 		log.Errorf("Unable to power off blade, %s, Error: %s", blade.Name, err)
 		return err
 	}
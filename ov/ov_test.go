@@ -5,7 +5,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/testconfig"
-	"github.com/docker/machine/libmachine/log"
 )
 
 //TODO: need to learn a better way of how integration testing works with bats
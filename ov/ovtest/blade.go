@@ -0,0 +1,386 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// Blade - a simulated server hardware resource with power state transitions,
+// power requests start a task that applies the new state when it completes
+type Blade struct {
+	mu           sync.Mutex
+	URI          string
+	Name         string
+	SerialNumber string
+	// State - current power state, "On", "Off", "PoweringOn", ...
+	State string
+	// Transition - when set, reported once after the task completes before
+	// the requested state is applied, "PoweringOn" for example
+	Transition string
+	// TaskPolls - number of polls a power task reports Running before it completes
+	TaskPolls int
+	// PendingPolls - number of polls a power task reports Pending, queued on a
+	// busy appliance, before it starts Running
+	PendingPolls int
+	// PowerMethod - http method accepted for power requests, PUT when 0
+	PowerMethod rest.Method
+	// TaskState - state power tasks end in, "Completed" when empty, any
+	// other state leaves the power state unchanged
+	TaskState string
+	// TaskError - message of the task error reported when TaskState isn't "Completed"
+	TaskError string
+	// Actual - when set, the power state a refresh reconciles a stale State to
+	Actual string
+	// UIDState - state of the uid light, "Off" when empty, set with a patch task
+	UIDState string
+	// OneTimeBoot - device the blade boots from once, set with a patch task
+	// and read back as "oneTimeBoot", left out when empty
+	OneTimeBoot string
+	// Statuses - hardware status reported on each read, the last one repeats, "OK" when empty
+	Statuses []string
+	// Cancellable - power tasks accept a cancel and end "Cancelled" without
+	// applying the power state, cancels answer 400 Bad Request otherwise
+	Cancellable bool
+	// HardwareState - server hardware state, "ApplyingProfile" for example, and
+	// RefreshState, "Refreshing" for example, left out when empty
+	HardwareState string
+	RefreshState  string
+	// MaintenanceMode - report the blade in maintenance mode
+	MaintenanceMode bool
+	// ApplyOnRead - apply a pending power request on the next read of the blade,
+	// as the appliance does when nobody polls the power task
+	ApplyOnRead bool
+	// Steps - progress updates of power tasks, one more is reported on each poll
+	// and all of them once the task is done
+	Steps []string
+	// Model - model of the blade, left out when empty
+	Model string
+	// LocationURI - enclosure the blade is in, Position - its bay
+	LocationURI string
+	Position    int
+
+	pending       string
+	transition    string
+	polls         int
+	queued        int
+	powerStates   []string
+	powerControls []string
+	activeTask    string
+	taskName      string
+	refreshes     int
+	cancelled     bool
+}
+
+// AddBlade - add a blade to the fake appliance at uri in the given power state
+func (f *Fake) AddBlade(uri, name, state string) *Blade {
+	b := &Blade{URI: uri, Name: name, SerialNumber: "OVTEST" + name, State: state}
+	f.mu.Lock()
+	f.blades = append(f.blades, b)
+	f.mu.Unlock()
+	f.Handle(rest.GET, uri, b.get)
+	for _, m := range []rest.Method{rest.PUT, rest.PATCH} {
+		f.Handle(m, uri+"/powerState", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+			return b.power(f.nextTaskURI(), f, method, options)
+		})
+	}
+	f.Handle(rest.PATCH, uri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return b.patch(f.nextTaskURI(), f, options)
+	})
+	f.Handle(rest.PUT, uri+"/refreshState", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return b.refresh(f.nextTaskURI(), f)
+	})
+	return b
+}
+
+// Refreshes - get the number of refresh requests made for the blade
+func (b *Blade) Refreshes() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.refreshes
+}
+
+// GetState - get the current power state of the blade
+func (b *Blade) GetState() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.State
+}
+
+// PowerRequests - get the power states requested for the blade so far
+func (b *Blade) PowerRequests() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.powerStates...)
+}
+
+// PowerControls - get the power controls of the power requests made for the blade so far
+func (b *Blade) PowerControls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.powerControls...)
+}
+
+func (b *Blade) get(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return json.Marshal(b.read())
+}
+
+// read - the server hardware resource as the appliance reports it, advancing the
+// hardware statuses and transitions like a get, b.mu must be held
+func (b *Blade) read() map[string]interface{} {
+	status := "OK"
+	if len(b.Statuses) > 0 {
+		status = b.Statuses[0]
+		if len(b.Statuses) > 1 {
+			b.Statuses = b.Statuses[1:]
+		}
+	}
+	uid := b.UIDState
+	if uid == "" {
+		uid = "Off"
+	}
+	if b.ApplyOnRead && b.activeTask != "" && !b.cancelled {
+		b.State = b.pending
+		b.endTask(b.activeTask)
+	}
+	resource := map[string]interface{}{
+		"type":         "server-hardware-3",
+		"uri":          b.URI,
+		"name":         b.Name,
+		"serialNumber": b.SerialNumber,
+		"powerState":   b.State,
+		"status":       status,
+		"uidState":     uid,
+	}
+	if b.Model != "" {
+		resource["model"] = b.Model
+	}
+	if b.OneTimeBoot != "" {
+		resource["oneTimeBoot"] = b.OneTimeBoot
+	}
+	if b.HardwareState != "" {
+		resource["state"] = b.HardwareState
+	}
+	if b.RefreshState != "" {
+		resource["refreshState"] = b.RefreshState
+	}
+	if b.MaintenanceMode {
+		resource["maintenanceMode"] = true
+	}
+	if b.LocationURI != "" {
+		resource["locationUri"] = b.LocationURI
+		resource["position"] = b.Position
+	}
+	if b.transition != "" && b.State == b.transition {
+		b.State, b.transition = b.pending, ""
+	}
+	return resource
+}
+
+func (b *Blade) power(taskuri string, f *Fake, method rest.Method, options interface{}) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	accepted := b.PowerMethod
+	if accepted == 0 {
+		accepted = rest.PUT
+	}
+	if method != accepted {
+		return nil, StatusError(http.StatusMethodNotAllowed, method.String()+" not allowed for power state")
+	}
+	var request struct {
+		PowerState   string `json:"powerState"`
+		PowerControl string `json:"powerControl"`
+	}
+	if err := decodeOptions(options, &request); err != nil || request.PowerState == "" {
+		return nil, StatusError(http.StatusBadRequest, "powerState is required")
+	}
+	b.powerStates = append(b.powerStates, request.PowerState)
+	b.powerControls = append(b.powerControls, request.PowerControl)
+	b.pending, b.polls, b.queued, b.cancelled = request.PowerState, 0, 0, false
+	b.activeTask, b.taskName = taskuri, "Power "+strings.ToLower(request.PowerState)
+	f.Handle(rest.GET, taskuri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return b.task(taskuri)
+	})
+	f.Handle(rest.PUT, taskuri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return b.cancel(taskuri, options)
+	})
+	if b.PendingPolls > 0 {
+		return b.powerTaskJSON(taskuri, "Pending", 0)
+	}
+	return b.powerTaskJSON(taskuri, "Running", 0)
+}
+
+func (b *Blade) patch(taskuri string, f *Fake, options interface{}) ([]byte, error) {
+	var ops []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+	if err := decodeOptions(options, &ops); err != nil || len(ops) == 0 {
+		return nil, StatusError(http.StatusBadRequest, "patch operations are required")
+	}
+	for _, op := range ops {
+		if op.Op != "replace" || (op.Path != "/uidState" && op.Path != "/oneTimeBoot") {
+			return nil, StatusError(http.StatusBadRequest, op.Op+" "+op.Path+" not supported")
+		}
+	}
+	f.Handle(rest.GET, taskuri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for _, op := range ops {
+			switch op.Path {
+			case "/uidState":
+				b.UIDState = op.Value
+			case "/oneTimeBoot":
+				b.OneTimeBoot = op.Value
+			}
+		}
+		return taskJSON(taskuri, "Completed", 100)
+	})
+	return taskJSON(taskuri, "Running", 0)
+}
+
+func (b *Blade) refresh(taskuri string, f *Fake) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refreshes++
+	f.Handle(rest.GET, taskuri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.Actual != "" {
+			b.State = b.Actual
+		}
+		return taskJSON(taskuri, "Completed", 100)
+	})
+	return taskJSON(taskuri, "Running", 0)
+}
+
+func (b *Blade) cancel(taskuri string, options interface{}) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var request struct {
+		TaskState string `json:"taskState"`
+	}
+	if err := decodeOptions(options, &request); err != nil || request.TaskState != "Cancelling" {
+		return nil, StatusError(http.StatusBadRequest, "only a Cancelling taskState can be set")
+	}
+	if !b.Cancellable {
+		return nil, StatusError(http.StatusBadRequest, "task is not cancellable")
+	}
+	b.cancelled, b.pending = true, ""
+	return b.powerTaskJSON(taskuri, "Cancelling", 100*b.polls/(b.TaskPolls+1))
+}
+
+func (b *Blade) task(taskuri string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancelled {
+		b.endTask(taskuri)
+		return b.powerTaskJSON(taskuri, "Cancelled", 100*b.polls/(b.TaskPolls+1))
+	}
+	if b.queued < b.PendingPolls {
+		b.queued++
+		return b.powerTaskJSON(taskuri, "Pending", 0)
+	}
+	if b.polls < b.TaskPolls {
+		b.polls++
+		return b.powerTaskJSON(taskuri, "Running", 100*b.polls/(b.TaskPolls+1))
+	}
+	b.endTask(taskuri)
+	if b.TaskState != "" && b.TaskState != "Completed" {
+		if b.TaskError != "" {
+			return json.Marshal(map[string]interface{}{
+				"type":       "TaskResourceV2",
+				"uri":        taskuri,
+				"taskState":  b.TaskState,
+				"taskErrors": []map[string]interface{}{{"message": b.TaskError, "recommendedActions": []string{"Retry the power request."}}},
+			})
+		}
+		return b.powerTaskJSON(taskuri, b.TaskState, 100)
+	}
+	if b.pending != "" {
+		b.State = b.pending
+		if b.Transition != "" {
+			b.State, b.transition = b.Transition, b.Transition
+		}
+	}
+	return b.powerTaskJSON(taskuri, "Completed", 100)
+}
+
+// endTask - the power task at taskuri is done, b.mu must be held
+func (b *Blade) endTask(taskuri string) {
+	if b.activeTask == taskuri {
+		b.activeTask = ""
+	}
+}
+
+// runningTask - the power task running on the blade as the task collection lists it,
+// without counting as a poll, nil when there is none, b.mu must be held
+func (b *Blade) runningTask() map[string]interface{} {
+	if b.activeTask == "" {
+		return nil
+	}
+	state := "Running"
+	if b.cancelled {
+		state = "Cancelling"
+	}
+	return b.powerTask(b.activeTask, state, 100*b.polls/(b.TaskPolls+1))
+}
+
+// powerTask - a power task, cancellable when the blade is, b.mu must be held
+func (b *Blade) powerTask(uri, state string, percent int) map[string]interface{} {
+	steps := len(b.Steps)
+	if (state == "Running" || state == "Cancelling") && b.polls+1 < steps {
+		steps = b.polls + 1
+	}
+	updates := []map[string]interface{}{}
+	for i, step := range b.Steps[:steps] {
+		updates = append(updates, map[string]interface{}{"id": i + 1, "statusUpdate": step})
+	}
+	return map[string]interface{}{
+		"type":                    "TaskResourceV2",
+		"uri":                     uri,
+		"name":                    b.taskName,
+		"taskState":               state,
+		"computedPercentComplete": percent,
+		"isCancellable":           b.Cancellable,
+		"progressUpdates":         updates,
+		"associatedResource":      map[string]interface{}{"resourceUri": b.URI, "resourceName": b.Name, "resourceCategory": "server-hardware"},
+	}
+}
+
+// powerTaskJSON - the json of a power task, b.mu must be held
+func (b *Blade) powerTaskJSON(uri, state string, percent int) ([]byte, error) {
+	return json.Marshal(b.powerTask(uri, state, percent))
+}
+
+func taskJSON(uri, state string, percent int) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":                    "TaskResourceV2",
+		"uri":                     uri,
+		"taskState":               state,
+		"computedPercentComplete": percent,
+	})
+}
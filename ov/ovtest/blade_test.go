@@ -0,0 +1,58 @@
+package ovtest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// getTestTask - make a rest call and decode the task it answers
+func getTestTask(t *testing.T, c rest.Client, method rest.Method, path string, options interface{}) map[string]interface{} {
+	data, err := c.RestAPICall(method, path, options)
+	assert.NoError(t, err, "%s %s threw error -> %s", method, path, err)
+	task := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(data, &task))
+	return task
+}
+
+// TestBladePower - a power request completes after TaskPolls and applies the state
+func TestBladePower(t *testing.T) {
+	f := NewFake()
+	c := f.NewRestClient()
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 1
+	b.Transition = "PoweringOn"
+
+	task := getTestTask(t, c, rest.PUT, "/rest/server-hardware/1/powerState", map[string]string{"powerState": "On"})
+	assert.Equal(t, "Running", task["taskState"])
+	uri := task["uri"].(string)
+
+	task = getTestTask(t, c, rest.GET, uri, nil)
+	assert.Equal(t, "Running", task["taskState"])
+	assert.Equal(t, "Off", b.GetState())
+
+	task = getTestTask(t, c, rest.GET, uri, nil)
+	assert.Equal(t, "Completed", task["taskState"])
+	assert.Equal(t, "PoweringOn", b.GetState())
+
+	hw := getTestTask(t, c, rest.GET, "/rest/server-hardware/1", nil)
+	assert.Equal(t, "PoweringOn", hw["powerState"])
+	assert.Equal(t, "On", b.GetState())
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+}
+
+// TestBladePowerMethod - only the PowerMethod is accepted for power requests
+func TestBladePowerMethod(t *testing.T) {
+	f := NewFake()
+	c := f.NewRestClient()
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.PowerMethod = rest.PATCH
+
+	_, err := c.RestAPICall(rest.PUT, "/rest/server-hardware/1/powerState", map[string]string{"powerState": "On"})
+	assert.Error(t, err)
+	_, err = c.RestAPICall(rest.PATCH, "/rest/server-hardware/1/powerState", map[string]string{})
+	assert.Error(t, err)
+	getTestTask(t, c, rest.PATCH, "/rest/server-hardware/1/powerState", map[string]string{"powerState": "On"})
+}
@@ -0,0 +1,154 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// Enclosure - a simulated enclosure, its device bays hold the blades added to
+// the fake with its uri as their LocationURI
+type Enclosure struct {
+	mu             sync.Mutex
+	URI            string
+	Name           string
+	Bays           int    // number of device bays
+	PowerMode      string // "RedundantPowerFeed" when empty
+	CapacityWatts  int
+	AllocatedWatts int // available watts are what is left of CapacityWatts
+
+	bayResets []string
+}
+
+// AddEnclosure - add an enclosure with bays device bays to the fake appliance at uri
+func (f *Fake) AddEnclosure(uri, name string, bays int) *Enclosure {
+	e := &Enclosure{URI: uri, Name: name, Bays: bays}
+	f.Handle(rest.GET, uri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return e.get(f)
+	})
+	f.Handle(rest.PATCH, uri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return e.patch(f.nextTaskURI(), f, options)
+	})
+	return e
+}
+
+// BayResets - get the bay power resets requested so far, "<bay>:<value>", "3:E-Fuse" for example
+func (e *Enclosure) BayResets() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.bayResets...)
+}
+
+// AddBlade - add a blade to the fake in bay of the enclosure
+func (e *Enclosure) AddBlade(f *Fake, uri string, bay int, state string) *Blade {
+	e.mu.Lock()
+	name := e.Name
+	e.mu.Unlock()
+	b := f.AddBlade(uri, name+", bay "+strconv.Itoa(bay), state)
+	b.LocationURI, b.Position = e.URI, bay
+	return b
+}
+
+func (e *Enclosure) get(f *Fake) ([]byte, error) {
+	f.mu.Lock()
+	blades := append([]*Blade(nil), f.blades...)
+	f.mu.Unlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bays := make([]map[string]interface{}, e.Bays)
+	for i := range bays {
+		bays[i] = map[string]interface{}{"bayNumber": i + 1, "devicePresence": "Absent", "deviceUri": nil}
+	}
+	for _, b := range blades {
+		b.mu.Lock()
+		if b.LocationURI == e.URI && b.Position >= 1 && b.Position <= e.Bays {
+			bays[b.Position-1]["devicePresence"] = "Present"
+			bays[b.Position-1]["deviceUri"] = b.URI
+		}
+		b.mu.Unlock()
+	}
+	mode := e.PowerMode
+	if mode == "" {
+		mode = "RedundantPowerFeed"
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":                "EnclosureV200",
+		"uri":                 e.URI,
+		"name":                e.Name,
+		"powerMode":           mode,
+		"powerCapacityWatts":  e.CapacityWatts,
+		"powerAllocatedWatts": e.AllocatedWatts,
+		"powerAvailableWatts": e.CapacityWatts - e.AllocatedWatts,
+		"deviceBayCount":      e.Bays,
+		"deviceBays":          bays,
+	})
+}
+
+// patch - replace /deviceBays/<bay>/bayPowerState with "E-Fuse" or "Reset", the
+// blade in the bay is off once the task completes
+func (e *Enclosure) patch(taskuri string, f *Fake, options interface{}) ([]byte, error) {
+	var ops []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+	if err := decodeOptions(options, &ops); err != nil || len(ops) == 0 {
+		return nil, StatusError(http.StatusBadRequest, "patch operations are required")
+	}
+	var bays []int
+	for _, op := range ops {
+		parts := strings.Split(op.Path, "/")
+		if op.Op != "replace" || len(parts) != 4 || parts[1] != "deviceBays" || parts[3] != "bayPowerState" ||
+			(op.Value != "E-Fuse" && op.Value != "Reset") {
+			return nil, StatusError(http.StatusBadRequest, op.Op+" "+op.Path+" "+op.Value+" not supported")
+		}
+		bay, err := strconv.Atoi(parts[2])
+		if err != nil || bay < 1 || bay > e.Bays {
+			return nil, StatusError(http.StatusBadRequest, "no device bay "+parts[2])
+		}
+		bays = append(bays, bay)
+	}
+	e.mu.Lock()
+	for i, op := range ops {
+		e.bayResets = append(e.bayResets, strconv.Itoa(bays[i])+":"+op.Value)
+	}
+	e.mu.Unlock()
+	f.Handle(rest.GET, taskuri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		f.mu.Lock()
+		blades := append([]*Blade(nil), f.blades...)
+		f.mu.Unlock()
+		for _, b := range blades {
+			b.mu.Lock()
+			for _, bay := range bays {
+				if b.LocationURI == e.URI && b.Position == bay {
+					b.State = "Off"
+				}
+			}
+			b.mu.Unlock()
+		}
+		return taskJSON(taskuri, "Completed", 100)
+	})
+	return taskJSON(taskuri, "Running", 0)
+}
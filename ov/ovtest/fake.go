@@ -0,0 +1,342 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ovtest - an in-memory OneView appliance for unit tests, set it as the
+// rest.Client Transport of an ov.OVClient:
+//
+//	f := ovtest.NewFake()
+//	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+//	c := &ov.OVClient{f.NewRestClient()}
+package ovtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// Handler - answers a single rest call made to the fake
+type Handler func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error)
+
+// Call - a rest call made to the fake
+type Call struct {
+	Method  rest.Method
+	Path    string     // path without the query string
+	Query   url.Values // query string of the call, nil when there is none
+	Options interface{}
+}
+
+// Fake - in-memory appliance, satisfies rest.ContextRestClient
+type Fake struct {
+	// Latency - time every call takes to answer, simulates a busy appliance
+	Latency time.Duration
+
+	mu          sync.Mutex
+	routes      map[string]Handler
+	blades      []*Blade
+	calls       []Call
+	tasks       int
+	inflight    int
+	maxinflight int
+}
+
+// NewFake - get a fake appliance that accepts any login and logout
+func NewFake() *Fake {
+	f := &Fake{routes: make(map[string]Handler)}
+	f.HandleJSON(rest.POST, "/rest/login-sessions", `{"sessionID":"ovtest-session"}`)
+	f.HandleJSON(rest.DELETE, "/rest/login-sessions", ``)
+	f.HandleJSON(rest.GET, "/rest/sessions/idle-timeout", `{"idleTimeout":3600000}`)
+	f.Handle(rest.GET, "/rest/tasks", f.tasksCollection)
+	f.Handle(rest.GET, "/rest/server-hardware", f.serverHardwareCollection)
+	return f
+}
+
+// serverHardwareCollection - answer the blades matching every "field='value'" filter,
+// values quote a single quote by doubling it like the appliance
+func (f *Fake) serverHardwareCollection(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	var query url.Values
+	if i := strings.Index(path, "?"); i >= 0 {
+		query, _ = url.ParseQuery(path[i+1:])
+	}
+	var terms []filterTerm
+	for _, filter := range query["filter"] {
+		t, err := parseFilterTerm(filter)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	f.mu.Lock()
+	blades := append([]*Blade(nil), f.blades...)
+	f.mu.Unlock()
+
+	members := []map[string]interface{}{}
+	for _, b := range blades {
+		b.mu.Lock()
+		resource := b.read()
+		b.mu.Unlock()
+		match := true
+		for _, t := range terms {
+			if !t.match(resource) {
+				match = false
+			}
+		}
+		if match {
+			members = append(members, resource)
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":    "server-hardware-list-3",
+		"members": members,
+		"count":   len(members),
+		"total":   len(members),
+	})
+}
+
+// filterTerm - a "field='value'" filter term, the value unquoted
+type filterTerm struct{ field, value string }
+
+// parseFilterTerm - parse a "field='value'" filter term, values quote a single quote
+// by doubling it like the appliance
+func parseFilterTerm(filter string) (filterTerm, error) {
+	i := strings.Index(filter, "=")
+	if i < 0 {
+		return filterTerm{}, StatusError(http.StatusBadRequest, "filter "+filter+" not supported by the fake")
+	}
+	value := strings.TrimSpace(filter[i+1:])
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return filterTerm{}, StatusError(http.StatusBadRequest, "filter "+filter+" not supported by the fake")
+	}
+	quoted := value[1 : len(value)-1]
+	if strings.Contains(strings.Replace(quoted, "''", "", -1), "'") {
+		return filterTerm{}, StatusError(http.StatusBadRequest, "filter "+filter+" has an unescaped quote")
+	}
+	return filterTerm{strings.TrimSpace(filter[:i]), strings.Replace(quoted, "''", "'", -1)}, nil
+}
+
+// match - true when the field of the resource has the value of the term
+func (t filterTerm) match(resource map[string]interface{}) bool {
+	return fmt.Sprint(resource[t.field]) == t.value
+}
+
+// tasksCollection - answer a task collection filtered with "uri='a' OR uri='b'"
+// from the handlers of each task, any other filter is matched against the power
+// tasks running on the blades, each filter term "field='value' OR field='value'"
+func (f *Fake) tasksCollection(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	var query url.Values
+	if i := strings.Index(path, "?"); i >= 0 {
+		query, _ = url.ParseQuery(path[i+1:])
+	}
+	filters := query["filter"]
+	for _, filter := range filters {
+		if !strings.HasPrefix(strings.TrimSpace(filter), "uri=") {
+			return f.runningTasks(filters)
+		}
+	}
+	members := []json.RawMessage{}
+	for _, filter := range filters {
+		for _, term := range strings.Split(filter, " OR ") {
+			uri := strings.Trim(strings.TrimPrefix(strings.TrimSpace(term), "uri="), "'")
+			f.mu.Lock()
+			h, ok := f.routes[routeKey(rest.GET, uri)]
+			f.mu.Unlock()
+			if !ok {
+				continue
+			}
+			data, err := h(ctx, rest.GET, uri, nil)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, data)
+		}
+	}
+	return json.Marshal(map[string]interface{}{"type": "TaskResourceCollectionV2", "members": members})
+}
+
+// runningTasks - answer the power tasks running on the blades that match every filter
+func (f *Fake) runningTasks(filters []string) ([]byte, error) {
+	var terms [][]filterTerm
+	for _, filter := range filters {
+		var any []filterTerm
+		for _, term := range strings.Split(filter, " OR ") {
+			t, err := parseFilterTerm(term)
+			if err != nil {
+				return nil, err
+			}
+			any = append(any, t)
+		}
+		terms = append(terms, any)
+	}
+	f.mu.Lock()
+	blades := append([]*Blade(nil), f.blades...)
+	f.mu.Unlock()
+
+	members := []map[string]interface{}{}
+	for _, b := range blades {
+		b.mu.Lock()
+		task := b.runningTask()
+		b.mu.Unlock()
+		if task == nil {
+			continue
+		}
+		fields := map[string]interface{}{
+			"uri":                                 task["uri"],
+			"name":                                task["name"],
+			"taskState":                           task["taskState"],
+			"associatedResource.resourceUri":      b.URI,
+			"associatedResource.resourceName":     b.Name,
+			"associatedResource.resourceCategory": "server-hardware",
+		}
+		match := true
+		for _, any := range terms {
+			matched := false
+			for _, t := range any {
+				matched = matched || t.match(fields)
+			}
+			match = match && matched
+		}
+		if match {
+			members = append(members, task)
+		}
+	}
+	return json.Marshal(map[string]interface{}{"type": "TaskResourceCollectionV2", "members": members})
+}
+
+// NewRestClient - get a rest client that sends its calls to the fake
+func (f *Fake) NewRestClient() rest.Client {
+	return rest.Client{
+		User:       "ovtest",
+		Password:   "ovtest",
+		Domain:     "LOCAL",
+		Endpoint:   "https://ovtest.invalid",
+		APIVersion: 120,
+		Transport:  f,
+	}
+}
+
+// Handle - answer method calls on path with h, replacing any existing handler
+func (f *Fake) Handle(method rest.Method, path string, h Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[routeKey(method, path)] = h
+}
+
+// HandleJSON - answer method calls on path with canned json
+func (f *Fake) HandleJSON(method rest.Method, path string, body string) {
+	f.Handle(method, path, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return []byte(body), nil
+	})
+}
+
+// HandleStatus - answer method calls on path with an error status
+func (f *Fake) HandleStatus(method rest.Method, path string, code int, details string) {
+	f.Handle(method, path, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return nil, StatusError(code, details)
+	})
+}
+
+// Calls - get the calls made to the fake so far
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// MaxInFlight - get the most calls the fake answered at the same time
+func (f *Fake) MaxInFlight() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxinflight
+}
+
+// RestAPICall - answer a rest call from the registered handlers
+func (f *Fake) RestAPICall(method rest.Method, path string, options interface{}) ([]byte, error) {
+	return f.RestAPICallContext(context.Background(), method, path, options)
+}
+
+// RestAPICallContext - answer a rest call from the registered handlers, routes
+// match the path without its query string, unknown paths answer 404 Not Found
+func (f *Fake) RestAPICallContext(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	call := Call{Method: method, Path: path, Options: options}
+	if i := strings.Index(path, "?"); i >= 0 {
+		call.Path = path[:i]
+		call.Query, _ = url.ParseQuery(path[i+1:])
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	h, ok := f.routes[routeKey(method, call.Path)]
+	f.inflight++
+	if f.inflight > f.maxinflight {
+		f.maxinflight = f.inflight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inflight--
+		f.mu.Unlock()
+	}()
+	if f.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(f.Latency):
+		}
+	}
+	if !ok {
+		return nil, StatusError(http.StatusNotFound, fmt.Sprintf("%s %s not found on fake", method, call.Path))
+	}
+	return h(ctx, method, path, options)
+}
+
+// StatusError - get the error the rest client returns for an error status
+func StatusError(code int, details string) error {
+	return &rest.StatusError{
+		StatusCode: code,
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		Details:    details,
+	}
+}
+
+// nextTaskURI - allocate a uri for a new task
+func (f *Fake) nextTaskURI() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tasks++
+	return fmt.Sprintf("/rest/tasks/ovtest-%d", f.tasks)
+}
+
+func routeKey(method rest.Method, path string) string {
+	return method.String() + " " + path
+}
+
+// decodeOptions - decode call options the way the appliance would see them
+func decodeOptions(options interface{}, v interface{}) error {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
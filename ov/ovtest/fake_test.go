@@ -0,0 +1,63 @@
+package ovtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFake - canned json, error statuses and unknown paths
+func TestFake(t *testing.T) {
+	f := NewFake()
+	c := f.NewRestClient()
+	f.HandleJSON(rest.GET, "/rest/version", `{"currentVersion":120}`)
+	f.HandleStatus(rest.GET, "/rest/busy", 503, "busy")
+
+	data, err := c.RestAPICall(rest.GET, "/rest/version", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"currentVersion":120}`, string(data))
+
+	_, err = c.RestAPICall(rest.GET, "/rest/busy", nil)
+	var serr *rest.StatusError
+	assert.True(t, errors.As(err, &serr))
+	assert.Equal(t, 503, serr.StatusCode)
+
+	_, err = c.RestAPICall(rest.DELETE, "/rest/version", nil)
+	assert.True(t, strings.Contains(err.Error(), "404 Not Found"))
+
+	calls := f.Calls()
+	assert.Equal(t, 3, len(calls))
+	assert.Equal(t, Call{Method: rest.GET, Path: "/rest/version"}, calls[0])
+}
+
+// TestFakeContext - cancelled contexts are not answered
+func TestFakeContext(t *testing.T) {
+	f := NewFake()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.RestAPICallContext(ctx, rest.POST, "/rest/login-sessions", nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, len(f.Calls()))
+}
+
+// TestFakeLatency - concurrent calls are tracked while they wait out the latency
+func TestFakeLatency(t *testing.T) {
+	f := NewFake()
+	f.Latency = 50 * time.Millisecond
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.RestAPICall(rest.POST, "/rest/login-sessions", nil)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 3, f.MaxInFlight())
+}
@@ -0,0 +1,93 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ov
+
+import (
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// AuditPhase - the point of a power change an AuditEvent is recorded at
+type AuditPhase int
+
+const (
+	A_SUBMIT AuditPhase = 1 + iota
+	A_COMPLETE
+)
+
+var auditphase = [...]string{
+	"Submit",   // Submit the power request was sent, or refused before sending.
+	"Complete", // Complete the power change finished, failed or timed out.
+}
+
+// String for type
+func (a AuditPhase) String() string { return auditphase[a-1] }
+
+// AuditEvent - record of a power change for an audit trail, see WithAudit
+type AuditEvent struct {
+	Phase        AuditPhase
+	User         string // appliance user making the change, empty for clients without one
+	Correlation  string // correlation id of the power operation, see rest.WithCorrelationID
+	Blade        string // name of the blade
+	BladeURI     utils.Nstring
+	SerialNumber utils.Nstring
+	Requested    PowerState
+	Control      PowerControl
+	State        PowerState    // power state of the blade when the event was recorded
+	TaskURI      utils.Nstring // empty when nothing was submitted
+	DryRun       bool
+	Err          error // nil when the phase succeeded
+	Started      time.Time
+	Time         time.Time
+}
+
+// WithAudit - call fn on every power request submitted and every power change completed,
+// failures and timeouts included, fn may be called from another goroutine
+func WithAudit(fn func(AuditEvent)) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Audit = fn
+	}
+}
+
+// audit - record phase of the power change to s with pc to the Audit callback
+func (pt *PowerTask) audit(phase AuditPhase, s PowerState, pc PowerControl, started time.Time, err error) {
+	pt.mu.Lock()
+	fn := pt.Audit
+	event := AuditEvent{
+		Phase:        phase,
+		Correlation:  pt.correlation,
+		Blade:        pt.Blade.Name,
+		BladeURI:     pt.Blade.URI,
+		SerialNumber: pt.Blade.SerialNumber,
+		Requested:    s,
+		Control:      pc,
+		State:        pt.State,
+		TaskURI:      pt.URI,
+		DryRun:       pt.DryRun,
+		Err:          err,
+		Started:      started,
+		Time:         time.Now(),
+	}
+	if c, ok := pt.Blade.Client.(*OVClient); ok && c != nil {
+		event.User = c.User
+	}
+	pt.mu.Unlock()
+	if fn != nil {
+		fn(event)
+	}
+}
@@ -0,0 +1,90 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPowerTaskAudit verify a power change is audited on submit and on completion,
+// failures and timeouts included
+func TestPowerTaskAudit(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var (
+		mu     sync.Mutex
+		events []AuditEvent
+	)
+	audit := WithAudit(func(e AuditEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+
+	var pt *PowerTask
+	state, err := pt.NewPowerTask(blade, WithWaitTime(0), audit).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, 2, len(events))
+	submit, complete := events[0], events[1]
+	assert.Equal(t, A_SUBMIT, submit.Phase)
+	assert.Equal(t, "ovtest", submit.User)
+	assert.Equal(t, "enc1, bay 1", submit.Blade)
+	assert.Equal(t, "OVTESTenc1, bay 1", submit.SerialNumber.String())
+	assert.Equal(t, P_ON, submit.Requested)
+	assert.Equal(t, P_MOMPRESS, submit.Control)
+	assert.False(t, submit.TaskURI.IsNil())
+	assert.NoError(t, submit.Err)
+	assert.Equal(t, A_COMPLETE, complete.Phase)
+	assert.Equal(t, P_ON, complete.State)
+	assert.Equal(t, submit.TaskURI, complete.TaskURI)
+	assert.False(t, complete.Time.Before(complete.Started))
+
+	b.TaskPolls = 10
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(1), audit).PowerOff()
+	assert.True(t, errors.Is(err, ErrPowerTimeout), "expected timeout, got %s", err)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, A_COMPLETE, events[3].Phase)
+	assert.True(t, errors.Is(events[3].Err, ErrPowerTimeout))
+
+	b.TaskPolls, b.TaskState = 0, "Error"
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), audit).PowerOff()
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	assert.Equal(t, 6, len(events))
+	assert.True(t, errors.Is(events[5].Err, ErrTaskFailed))
+	assert.Equal(t, "Complete", events[5].Phase.String())
+}
+
+// TestPowerTaskAuditCorrelation verify the events of a power operation share its
+// correlation id, the one of the context when there is one
+func TestPowerTaskAuditCorrelation(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var events []AuditEvent
+	audit := WithAudit(func(e AuditEvent) { events = append(events, e) })
+
+	var pt *PowerTask
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), audit).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, 2, len(events))
+	assert.NotEmpty(t, events[0].Correlation)
+	assert.Equal(t, events[0].Correlation, events[1].Correlation)
+
+	ctx := rest.WithCorrelationID(context.Background(), "change-42")
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), audit).PowerExecutorContext(ctx, P_OFF)
+	assert.NoError(t, err, "PowerExecutorContext threw error -> %s", err)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, "change-42", events[2].Correlation)
+	assert.Equal(t, "change-42", events[3].Correlation)
+}
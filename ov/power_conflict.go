@@ -0,0 +1,149 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ConflictPolicy - what a power request does when the blade already has a power task running
+type ConflictPolicy int
+
+const (
+	C_IGNORE ConflictPolicy = iota
+	C_FAIL
+	C_WAIT
+)
+
+var conflictpolicy = [...]string{
+	"Ignore", // Ignore submit without looking for running power tasks.
+	"Fail",   // Fail with ErrPowerTaskConflict when a power task is running.
+	"Wait",   // Wait for the running power tasks, then plan the request again.
+}
+
+// String for type
+func (p ConflictPolicy) String() string { return conflictpolicy[p] }
+
+// ErrPowerTaskConflict - another power task is running on the blade, see WithConflictCheck
+var ErrPowerTaskConflict = errors.New("Power task already running")
+
+// WithConflictCheck - look for power tasks already running on the blade before submitting,
+// and fail or wait for them depending on policy, C_IGNORE by default
+func WithConflictCheck(policy ConflictPolicy) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Conflicts = policy
+	}
+}
+
+// activeTaskStatesFilter - filter on the task states that aren't terminal
+func activeTaskStatesFilter() string {
+	var terms []string
+	for i := range taskstate {
+		if ts := TaskState(i + 1); !ts.IsTerminal() && ts != T_UNKNOWN {
+			terms = append(terms, fmt.Sprintf("taskState=%s", filterValue(ts.String())))
+		}
+	}
+	return strings.Join(terms, " OR ")
+}
+
+// GetRunningTasks - get the tasks that aren't terminal yet for the resource at uri
+func (c *OVClient) GetRunningTasks(uri utils.Nstring) ([]Task, error) {
+	tasks, err := c.GetTasks([]string{
+		fmt.Sprintf("associatedResource.resourceUri=%s", filterValue(uri.String())),
+		activeTaskStatesFilter(),
+	}, "")
+	return tasks.Members, err
+}
+
+// GetActivePowerTasks - get the power tasks that aren't terminal yet across the
+// appliance, to spot power changes that are stuck
+func (c *OVClient) GetActivePowerTasks() ([]Task, error) {
+	tasks, err := c.GetTasks([]string{
+		fmt.Sprintf("associatedResource.resourceCategory=%s", filterValue("server-hardware")),
+		activeTaskStatesFilter(),
+	}, "created:ascending")
+	if err != nil {
+		return nil, err
+	}
+	var power []Task
+	for _, t := range tasks.Members {
+		if t.IsPowerTask() {
+			power = append(power, t)
+		}
+	}
+	return power, nil
+}
+
+// powertasknames - names the appliance gives tasks changing the power state, lower case
+var powertasknames = []string{"power on", "power off", "reset", "cold boot", "momentary press", "press and hold"}
+
+// IsPowerTask - true for a task changing the power state of a blade
+func (t *Task) IsPowerTask() bool {
+	name := strings.ToLower(strings.TrimSpace(t.Name))
+	for _, n := range powertasknames {
+		if strings.HasPrefix(name, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// runningTaskClient - a ServerHardwareClient that can list running tasks, *OVClient
+type runningTaskClient interface {
+	GetRunningTasks(uri utils.Nstring) ([]Task, error)
+}
+
+// checkConflicts - apply policy to the power tasks running on the blade, true when
+// one was waited on and the request must be planned again
+func (pt *PowerTask) checkConflicts(policy ConflictPolicy) (bool, error) {
+	pt.mu.Lock()
+	blade, timeout, wait, min := pt.Blade, pt.Timeout, pt.WaitTime, pt.MinWaitTime
+	pt.mu.Unlock()
+	client, ok := blade.Client.(runningTaskClient)
+	if policy == C_IGNORE || !ok {
+		return false, nil
+	}
+	tasks, err := client.GetRunningTasks(blade.URI)
+	if err != nil {
+		return false, fmt.Errorf("Error getting running tasks for %s: %w", blade.Name, err)
+	}
+	waited := false
+	for i := range tasks {
+		t := &tasks[i]
+		if !t.IsPowerTask() || t.URI.IsNil() {
+			continue
+		}
+		if policy == C_FAIL {
+			return false, fmt.Errorf("%w, %s on %s, %s", ErrPowerTaskConflict, t.Name, blade.Name, t.URI)
+		}
+		log.Infof("Waiting on running task, %s, for %s before powering it.", t.Name, blade.Name)
+		t.Timeout, t.WaitTime, t.MinWaitTime, t.TaskIsDone = timeout, wait, min, false
+		if _, _, err := pollTask(context.Background(), t, timeout, nil); err != nil && !errors.Is(err, ErrTaskFailed) {
+			return false, err
+		}
+		if !t.TaskIsDone {
+			return false, fmt.Errorf("%w, %s on %s still running after %d checks", ErrPowerTaskConflict, t.Name, blade.Name, timeout)
+		}
+		waited = true
+	}
+	return waited, nil
+}
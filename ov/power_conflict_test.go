@@ -0,0 +1,104 @@
+package ov
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetRunningTasks verify the running tasks of a resource are found with their filters
+func TestGetRunningTasks(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	f.AddBlade("/rest/server-hardware/2", "enc1, bay 2", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	tasks, err := c.GetRunningTasks(blade.URI)
+	assert.NoError(t, err, "GetRunningTasks threw error -> %s", err)
+	assert.Equal(t, 0, len(tasks))
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade)
+	pt.SubmitPowerState(P_ON)
+	tasks, err = c.GetRunningTasks(blade.URI)
+	assert.NoError(t, err, "GetRunningTasks threw error -> %s", err)
+	assert.Equal(t, 1, len(tasks))
+	assert.Equal(t, pt.URI, tasks[0].URI)
+	assert.Equal(t, "Power on", tasks[0].Name)
+	assert.True(t, tasks[0].IsPowerTask())
+	tasks, err = c.GetRunningTasks("/rest/server-hardware/2")
+	assert.NoError(t, err, "GetRunningTasks threw error -> %s", err)
+	assert.Equal(t, 0, len(tasks))
+
+	filter := f.Calls()[len(f.Calls())-1].Query["filter"]
+	assert.Equal(t, []string{"associatedResource.resourceUri='/rest/server-hardware/2'",
+		"taskState='New' OR taskState='Pending' OR taskState='Running' OR taskState='Starting' OR " +
+			"taskState='Stopping' OR taskState='Suspended' OR taskState='Cancelling'"}, filter)
+	assert.False(t, (&Task{Name: "Refresh"}).IsPowerTask())
+}
+
+// TestGetActivePowerTasks verify the running power tasks of every blade are listed
+func TestGetActivePowerTasks(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	f.AddBlade("/rest/server-hardware/2", "enc1, bay 2", "On")
+	f.AddBlade("/rest/server-hardware/3", "enc1, bay 3", "Off")
+
+	tasks, err := c.GetActivePowerTasks()
+	assert.NoError(t, err, "GetActivePowerTasks threw error -> %s", err)
+	assert.Equal(t, 0, len(tasks))
+
+	var pt *PowerTask
+	for uri, s := range map[string]PowerState{"/rest/server-hardware/1": P_ON, "/rest/server-hardware/2": P_OFF} {
+		blade, err := c.GetServerHardware(utils.NewNstring(uri))
+		assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+		pt.NewPowerTask(blade).SubmitPowerState(s)
+	}
+	tasks, err = c.GetActivePowerTasks()
+	assert.NoError(t, err, "GetActivePowerTasks threw error -> %s", err)
+	assert.Equal(t, 2, len(tasks))
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"Power off", "Power on"}, names)
+	filter := f.Calls()[len(f.Calls())-1].Query["filter"]
+	assert.Equal(t, "associatedResource.resourceCategory='server-hardware'", filter[0])
+}
+
+// TestPowerExecutorConflict verify a power task already running on the blade fails or
+// is waited on before submitting, and nothing is sent when it set the desired state
+func TestPowerExecutorConflict(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt.NewPowerTask(blade).SubmitPowerState(P_ON)
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), WithConflictCheck(C_FAIL)).PowerOn()
+	assert.True(t, errors.Is(err, ErrPowerTaskConflict), "expected conflict, got %s", err)
+	assert.Contains(t, err.Error(), "Power on on enc1, bay 1")
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+
+	state, err := pt.NewPowerTask(blade, WithWaitTime(0), WithConflictCheck(C_WAIT)).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+
+	state, err = pt.NewPowerTask(blade, WithWaitTime(0), WithConflictCheck(C_FAIL)).PowerOff()
+	assert.NoError(t, err, "PowerOff threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, []string{"On", "Off"}, b.PowerRequests())
+	assert.Equal(t, "Wait", C_WAIT.String())
+}
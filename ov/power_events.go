@@ -0,0 +1,102 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ov
+
+import (
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// PowerEventType - the kind of state transition a PowerEvent reports
+type PowerEventType int
+
+const (
+	E_SUBMITTED PowerEventType = 1 + iota
+	E_RUNNING
+	E_STATE
+	E_COMPLETED
+	E_FAILED
+)
+
+var powereventtype = [...]string{
+	"Submitted", // Submitted the appliance accepted the power request, TaskURI is set.
+	"Running",   // Running the power task started running.
+	"State",     // State the power state of the blade changed, from Previous to State.
+	"Completed", // Completed the power change is done and verified.
+	"Failed",    // Failed the power change failed, timed out or was cancelled, Err says why.
+}
+
+// String for type
+func (e PowerEventType) String() string { return powereventtype[e-1] }
+
+// PowerEventBuffer - events buffered for a slow reader of Events, later ones are dropped
+const PowerEventBuffer = 32
+
+// PowerEvent - a state transition of a power change, see Events
+type PowerEvent struct {
+	Type     PowerEventType
+	Blade    string
+	TaskURI  utils.Nstring
+	State    PowerState // power state of the blade when the event was sent
+	Previous PowerState // power state before the change, for E_STATE
+	Err      error      // why the change failed, for E_FAILED
+	Time     time.Time
+}
+
+// Events - get a channel of the state transitions of the power change running or
+// the next one started, closed when PowerExecutor returns, events are dropped
+// instead of blocking the power change when the reader falls PowerEventBuffer behind
+func (pt *PowerTask) Events() <-chan PowerEvent {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pt.events == nil {
+		pt.events = make(chan PowerEvent, PowerEventBuffer)
+	}
+	return pt.events
+}
+
+// emit - send e to the Events channel when there is one, without blocking
+func (pt *PowerTask) emit(e PowerEvent) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pt.events == nil {
+		return
+	}
+	e.Blade, e.Time = pt.Blade.Name, time.Now()
+	if e.TaskURI.IsNil() {
+		e.TaskURI = pt.URI
+	}
+	if e.Type != E_STATE {
+		e.State = pt.State
+	}
+	select {
+	case pt.events <- e:
+	default:
+		log.Debugf("Power event %s for %s dropped, nobody is reading", e.Type, e.Blade)
+	}
+}
+
+// closeEvents - close the Events channel of the power change that finished
+func (pt *PowerTask) closeEvents() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pt.events != nil {
+		close(pt.events)
+		pt.events = nil
+	}
+}
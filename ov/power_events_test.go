@@ -0,0 +1,64 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPowerTaskEvents verify the transitions of a power change are sent in order and
+// the channel closed when the executor returns
+func TestPowerTaskEvents(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	events := pt.Events()
+	_, err = pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	var types []PowerEventType
+	var change PowerEvent
+	for e := range events {
+		types = append(types, e.Type)
+		assert.Equal(t, "enc1, bay 1", e.Blade)
+		if e.Type == E_STATE {
+			change = e
+		}
+	}
+	assert.Equal(t, []PowerEventType{E_SUBMITTED, E_RUNNING, E_STATE, E_COMPLETED}, types)
+	assert.Equal(t, P_OFF, change.Previous)
+	assert.Equal(t, P_ON, change.State)
+	assert.False(t, change.TaskURI.IsNil())
+
+	// a failure is reported, and nobody reading never blocks the executor
+	b.TaskState = "Error"
+	events = pt.Events()
+	for i := 0; i < PowerEventBuffer+1; i++ {
+		pt.emit(PowerEvent{Type: E_RUNNING})
+	}
+	_, err = pt.PowerOff()
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	n := 0
+	for range events {
+		n++
+	}
+	assert.Equal(t, PowerEventBuffer, n)
+
+	events = pt.Events()
+	_, err = pt.PowerOff()
+	assert.Error(t, err)
+	var last PowerEvent
+	for e := range events {
+		last = e
+	}
+	assert.Equal(t, E_FAILED, last.Type)
+	assert.True(t, errors.Is(last.Err, ErrTaskFailed))
+	assert.Equal(t, "Failed", last.Type.String())
+}
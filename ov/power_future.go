@@ -0,0 +1,62 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"context"
+)
+
+// PowerFuture - handle of a power change running in the background, see SubmitAsync
+type PowerFuture struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	state  PowerState
+	err    error
+}
+
+// SubmitAsync - start PowerExecutor(s) in a goroutine and return right away with a
+// handle to wait on, poll or cancel the power change
+func (pt *PowerTask) SubmitAsync(s PowerState) *PowerFuture {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &PowerFuture{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(f.done)
+		defer cancel()
+		f.state, f.err = pt.powerExecutor(ctx, s, P_MOMPRESS)
+	}()
+	return f
+}
+
+// Done - closed once the power change is done, Result doesn't block from then on
+func (f *PowerFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result - wait for the power change and get what PowerExecutor returned,
+// context.Canceled once it was cancelled
+func (f *PowerFuture) Result() (PowerState, error) {
+	<-f.done
+	return f.state, f.err
+}
+
+// Cancel - stop waiting on the power change, as for a cancelled PowerExecutorContext
+// its power task is cancelled when the appliance reports it as cancellable,
+// nothing is done once the change is done
+func (f *PowerFuture) Cancel() {
+	f.cancel()
+}
@@ -0,0 +1,63 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPowerTaskSubmitAsync verify the future is done with the executor result and
+// a cancel stops the wait, cancelling the power task on the appliance
+func TestPowerTaskSubmitAsync(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	future := pt.SubmitAsync(P_ON)
+	select {
+	case <-future.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("power change never done")
+	}
+	state, err := future.Result()
+	assert.NoError(t, err, "SubmitAsync threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	future.Cancel() // nothing to cancel once done
+	state, err = future.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, P_ON, state)
+
+	// the task never completes before the cancel
+	b.TaskPolls = 1000
+	b.Cancellable = true
+	pt = pt.NewPowerTask(blade, WithWaitTime(10*time.Millisecond), WithTimeout(1000))
+	future = pt.SubmitAsync(P_OFF)
+	select {
+	case <-future.Done():
+		t.Fatal("power change done before the cancel")
+	case <-time.After(50 * time.Millisecond):
+	}
+	future.Cancel()
+	_, err = future.Result()
+	assert.True(t, errors.Is(err, context.Canceled), "expected cancelled, got %s", err)
+	// the appliance is asked to cancel the abandoned task in the background
+	cancelled := false
+	for wait := 0; wait < 100 && !cancelled; wait++ {
+		for _, call := range f.Calls() {
+			cancelled = cancelled || (call.Method == rest.PUT && strings.HasPrefix(call.Path, "/rest/tasks/"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, cancelled, "the power task is cancelled")
+}
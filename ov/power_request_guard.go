@@ -0,0 +1,108 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+var (
+	// ErrIdempotencyKeyReused - the idempotency key was already used for another power request
+	ErrIdempotencyKeyReused = errors.New("Error idempotency key already used for a different power request")
+	// ErrPowerRequestUnconfirmed - the keyed power request was sent but its answer wasn't
+	// read, it may still apply, Forget the key to submit it again
+	ErrPowerRequestUnconfirmed = errors.New("Error power request was sent without a confirmed task")
+)
+
+// PowerRequestGuard - remembers each keyed power request from before it is sent, a
+// retried request with the same key polls the first task instead of toggling the
+// blade again. Share one between the attempts of a caller, a failed task is reported
+// again until the key is forgotten.
+type PowerRequestGuard struct {
+	mu       sync.Mutex
+	requests map[string]guardedPowerRequest
+}
+
+type guardedPowerRequest struct {
+	blade   utils.Nstring
+	desired PowerState
+	task    utils.Nstring // empty while the request is sent or its answer was lost
+}
+
+// NewPowerRequestGuard - get an empty guard
+func NewPowerRequestGuard() *PowerRequestGuard {
+	return &PowerRequestGuard{requests: make(map[string]guardedPowerRequest)}
+}
+
+// reserve - claim key for a power request about to be sent, false when the key was
+// claimed before, with the task of that request if its answer was read
+func (g *PowerRequestGuard) reserve(key string, blade utils.Nstring, s PowerState) (utils.Nstring, bool, error) {
+	if g == nil || key == "" {
+		return "", true, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if r, ok := g.requests[key]; ok {
+		if r.blade != blade || r.desired != s {
+			return "", false, fmt.Errorf("%w, %q was power %s for %s", ErrIdempotencyKeyReused, key, powerStateName(r.desired), string(r.blade))
+		}
+		return r.task, false, nil
+	}
+	if g.requests == nil {
+		g.requests = make(map[string]guardedPowerRequest)
+	}
+	g.requests[key] = guardedPowerRequest{blade: blade, desired: s}
+	return "", true, nil
+}
+
+// confirm - remember the task the request claimed with key got
+func (g *PowerRequestGuard) confirm(key string, task utils.Nstring) {
+	if g == nil || key == "" {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if r, ok := g.requests[key]; ok {
+		r.task = task
+		g.requests[key] = r
+	}
+}
+
+// Forget - drop the request recorded for key, the next request with key is submitted
+func (g *PowerRequestGuard) Forget(key string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.requests, key)
+}
+
+// WithIdempotencyKey - submit the power request at most once for key, retries with
+// the same key and guard poll the task of the first request, or return
+// ErrPowerRequestUnconfirmed while its answer is unknown and the blade hasn't changed
+func WithIdempotencyKey(guard *PowerRequestGuard, key string) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Guard = guard
+		pt.IdempotencyKey = key
+	}
+}
@@ -0,0 +1,156 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPowerRequestGuard verify a retried keyed request polls the first task
+func TestPowerRequestGuard(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	guard := NewPowerRequestGuard()
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithIdempotencyKey(guard, "power-on-1"))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+
+	// the retry finds the blade on, polls the first task and never presses again
+	retry := pt.NewPowerTask(blade, WithWaitTime(0), WithIdempotencyKey(guard, "power-on-1"))
+	state, err = retry.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, utils.Nstring("/rest/tasks/ovtest-1"), retry.URI)
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+
+	other := pt.NewPowerTask(blade, WithWaitTime(0), WithIdempotencyKey(guard, "power-on-1"))
+	_, err = other.PowerExecutor(P_OFF)
+	assert.True(t, errors.Is(err, ErrIdempotencyKeyReused), "expected ErrIdempotencyKeyReused, got %v", err)
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+
+	guard.Forget("power-on-1")
+	_, err = other.PowerExecutor(P_OFF)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, []string{"On", "Off"}, b.PowerRequests())
+
+	var nilguard *PowerRequestGuard
+	nilguard.Forget("power-on-1")
+	nilguard.confirm("power-on-1", "/rest/tasks/1")
+	_, claimed, err := nilguard.reserve("power-on-1", blade.URI, P_ON)
+	assert.True(t, claimed)
+	assert.NoError(t, err)
+}
+
+// TestPowerRequestGuardConcurrent verify concurrent requests with one key press once
+func TestPowerRequestGuardConcurrent(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	guard := NewPowerRequestGuard()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each request uses its own client, like PowerExecutorBulk
+			own := blade
+			own.Client = c.Clone()
+			var pt *PowerTask
+			pt = pt.NewPowerTask(own, WithWaitTime(time.Millisecond), WithIdempotencyKey(guard, "power-on-1"))
+			pt.PowerExecutor(P_ON)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+}
+
+// TestPowerRequestGuardLostAnswer verify a keyed request sent without an answer isn't sent again
+func TestPowerRequestGuardLostAnswer(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	code, puts := http.StatusServiceUnavailable, 0
+	f.Handle(rest.PUT, "/rest/server-hardware/1/powerState", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		puts++
+		return nil, ovtest.StatusError(code, "lost answer")
+	})
+
+	guard := NewPowerRequestGuard()
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithIdempotencyKey(guard, "power-on-1"))
+	_, err = pt.PowerExecutor(P_ON)
+	assert.Error(t, err)
+	_, err = pt.PowerExecutor(P_ON)
+	assert.True(t, errors.Is(err, ErrPowerRequestUnconfirmed), "expected ErrPowerRequestUnconfirmed, got %v", err)
+	assert.Equal(t, 1, puts)
+
+	// a refused request applied nothing, the key is free again
+	guard.Forget("power-on-1")
+	code = http.StatusBadRequest
+	_, err = pt.PowerExecutor(P_ON)
+	assert.Error(t, err)
+	_, err = pt.PowerExecutor(P_ON)
+	assert.False(t, errors.Is(err, ErrPowerRequestUnconfirmed), "expected the request sent again, got %v", err)
+	assert.Equal(t, 3, puts)
+}
+
+// TestPowerExecutorLostAnswer verify a request applied before its answer was lost isn't sent again
+func TestPowerExecutorLostAnswer(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		code    int
+		applied bool
+		fails   bool
+	}{
+		{"applied then 503", http.StatusServiceUnavailable, true, false},
+		{"not applied 503", http.StatusServiceUnavailable, false, true},
+		{"applied then 400", http.StatusBadRequest, true, true},
+	} {
+		f := ovtest.NewFake()
+		c := &OVClient{f.NewRestClient()}
+		b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+		blade, err := c.GetServerHardware("/rest/server-hardware/1")
+		assert.NoError(t, err, "%s: GetServerHardware threw error -> %s", test.name, err)
+		puts := 0
+		f.Handle(rest.PUT, "/rest/server-hardware/1/powerState", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+			puts++
+			if test.applied {
+				b.State = "On"
+			}
+			return nil, ovtest.StatusError(test.code, "lost answer")
+		})
+
+		var pt *PowerTask
+		pt = pt.NewPowerTask(blade, WithWaitTime(0))
+		state, err := pt.PowerExecutor(P_ON)
+		assert.Equal(t, 1, puts, test.name)
+		if test.fails {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, "%s: PowerExecutor threw error -> %s", test.name, err)
+		assert.Equal(t, P_ON, state, test.name)
+	}
+	assert.True(t, isTransientError(errors.New("connection reset by peer")))
+	assert.False(t, isTransientError(context.Canceled))
+}
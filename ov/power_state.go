@@ -18,13 +18,17 @@ limitations under the License.
 package ov
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
+	"github.com/HewlettPackard/oneview-golang/utils"
 )
 
 // Create a PowerState type
@@ -39,17 +43,144 @@ type Power struct {
 const (
 	P_ON PowerState = 1 + iota
 	P_OFF
-	P_UKNOWN
+	P_UNKNOWN
+	P_POWERINGON
+	P_POWERINGOFF
+	P_RESETTING
 )
 
+// P_UKNOWN - misspelled alias of P_UNKNOWN kept for existing callers
+// Deprecated: use P_UNKNOWN
+const P_UKNOWN = P_UNKNOWN
+
 var powerstates = [...]string{
 	"On",
 	"Off",
 	"UNKNOWN",
+	"PoweringOn",  // transitioning to On
+	"PoweringOff", // transitioning to Off
+	"Resetting",   // reset or cold booted, returning to On
+}
+
+// powerstatesupper - powerstates upper cased once for Equal and ParsePowerState
+var powerstatesupper = func() (upper [len(powerstates)]string) {
+	for i, ps := range powerstates {
+		upper[i] = strings.ToUpper(ps)
+	}
+	return upper
+}()
+
+func (p PowerState) String() string { return powerstates[p-1] }
+
+// Equal - case insensitive match of s, ignoring surrounding whitespace
+func (p PowerState) Equal(s string) bool {
+	if p < 1 || int(p) > len(powerstatesupper) {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(s), powerstatesupper[p-1])
+}
+
+// ParsePowerState - get the PowerState for a power state reported by the appliance,
+// returns P_UNKNOWN and ErrUnknownPowerState when s isn't a known power state
+func ParsePowerState(s string) (PowerState, error) {
+	t := strings.TrimSpace(s)
+	for i, ps := range powerstatesupper {
+		if strings.EqualFold(t, ps) {
+			return PowerState(i + 1), nil
+		}
+	}
+	return P_UNKNOWN, fmt.Errorf("%w %q", ErrUnknownPowerState, s)
 }
 
-func (p PowerState) String() string      { return powerstates[p-1] }
-func (p PowerState) Equal(s string) bool { return (strings.ToUpper(s) == strings.ToUpper(p.String())) }
+// MarshalJSON - marshal as the api value, "On", "Off", "Unknown", ..., see APIValue
+func (p PowerState) MarshalJSON() ([]byte, error) {
+	v := p.APIValue()
+	if v == "" {
+		return nil, fmt.Errorf("%w %d", ErrUnknownPowerState, int(p))
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON - unmarshal from the api value, case insensitive, null is ignored
+func (p *PowerState) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	state, err := ParsePowerState(s)
+	if err != nil {
+		return err
+	}
+	*p = state
+	return nil
+}
+
+// APIValue - the exact value the appliance uses, "On", "Off", "Unknown", ...,
+// empty for a PowerState that isn't valid
+func (p PowerState) APIValue() string {
+	if p < 1 || int(p) > len(powerstates) {
+		return ""
+	}
+	if p == P_UNKNOWN {
+		return "Unknown"
+	}
+	return powerstates[p-1]
+}
+
+// Lower - lower case APIValue for logs and urls, "on", "off", "poweringon", ...
+func (p PowerState) Lower() string { return strings.ToLower(p.APIValue()) }
+
+// IsTransitional - true when the blade is still moving between power states,
+// a blade Resetting returns to On
+func (p PowerState) IsTransitional() bool {
+	return p == P_POWERINGON || p == P_POWERINGOFF || p == P_RESETTING
+}
+
+// IsRequestable - true for the states a power request can ask for, P_ON and P_OFF,
+// the others are only reported by the appliance
+func (p PowerState) IsRequestable() bool { return p == P_ON || p == P_OFF }
+
+var (
+	// ErrNoBladeHardware - the blade has no server hardware uri to manage power with
+	ErrNoBladeHardware = errors.New("Can't get power on blade without hardware")
+	// ErrNoBladeClient - the blade has no Client to reach the appliance with, as for
+	// a ServerHardware decoded from json
+	ErrNoBladeClient = errors.New("ServerHardware has no Client configured")
+	// ErrUnknownPowerState - the blade reported a power state that isn't recognized
+	ErrUnknownPowerState = errors.New("Un-known power state")
+	// ErrPowerTimeout - the power task didn't complete before Timeout, see PowerTimeoutError
+	ErrPowerTimeout = errors.New("Power state timed out")
+	// ErrPowerStateMismatch - the blade didn't reach the requested power state
+	ErrPowerStateMismatch = errors.New("Power state not reached")
+	// ErrPowerStateAbsent - the blade was read without a power state, worth reading again
+	ErrPowerStateAbsent = errors.New("Power state not reported")
+	// ErrHardwareNotHealthy - the blade hardware status didn't reach OK in time
+	ErrHardwareNotHealthy = errors.New("Hardware status not OK")
+	// ErrPowerStateNotRequestable - the power state can be reported but not requested, see IsRequestable
+	ErrPowerStateNotRequestable = errors.New("Power state can't be requested")
+	// ErrInvalidPowerRequest - the power control can't be used with the power state, see ValidatePowerRequest
+	ErrInvalidPowerRequest = errors.New("Invalid power request")
+	// ErrPowerResetOff - a reset was asked for a blade that isn't on, see Reboot
+	ErrPowerResetOff = errors.New("Blade is not on, it can't be reset")
+)
+
+// PowerTimeoutError - returned when a power task does not complete before Timeout
+type PowerTimeoutError struct {
+	State   PowerState    // desired power state
+	Blade   string        // name of the blade
+	Elapsed time.Duration // time spent waiting on the power task
+}
+
+// Error for type
+func (e *PowerTimeoutError) Error() string {
+	return fmt.Sprintf("Power %s state timed out for %s after %s.", e.State, e.Blade, e.Elapsed)
+}
+
+// Is - a PowerTimeoutError matches ErrPowerTimeout with errors.Is
+func (e *PowerTimeoutError) Is(target error) bool { return target == ErrPowerTimeout }
 
 // Power control
 type PowerControl int
@@ -58,74 +189,523 @@ const (
 	P_COLDBOOT PowerControl = 1 + iota
 	P_MOMPRESS
 	P_RESET
+	P_PRESSANDHOLD
 )
 
 var powercontrols = [...]string{
 	"ColdBoot", // ColdBoot       - A hard reset that immediately removes power from the server
 	//                hardware and then restarts the server after approximately six seconds.
 	"MomentaryPress", // MomentaryPress - Power on or a normal (soft) power off,
-	//                  depending on powerState. PressAndHold
-	//                  An immediate (hard) shutdown.
-	"Reset", // Reset          - A normal server reset that resets the device in an orderly sequence.
+	//                  depending on powerState.
+	"Reset",        // Reset          - A normal server reset that resets the device in an orderly sequence.
+	"PressAndHold", // PressAndHold   - An immediate (hard) shutdown.
+}
+
+// String - the api value, empty for a zero PowerControl so the appliance picks the default
+func (pc PowerControl) String() string {
+	if pc < 1 || int(pc) > len(powercontrols) {
+		return ""
+	}
+	return powercontrols[pc-1]
 }
 
-func (pc PowerControl) String() string { return powercontrols[pc-1] }
+// APIValue - the exact value the appliance uses, same as String
+func (pc PowerControl) APIValue() string { return pc.String() }
+
+// Lower - lower case APIValue for logs and urls, "momentarypress", ...
+func (pc PowerControl) Lower() string { return strings.ToLower(pc.APIValue()) }
+
+// ValidatePowerRequest - check that state can be requested with control before it is
+// sent, On takes MomentaryPress, Reset or ColdBoot, Off takes MomentaryPress or
+// PressAndHold, a zero control lets the appliance pick the default
+func ValidatePowerRequest(state PowerState, control PowerControl) error {
+	if !state.IsRequestable() {
+		return fmt.Errorf("%w, %s", ErrPowerStateNotRequestable, powerStateName(state))
+	}
+	if control == 0 {
+		return nil
+	}
+	if control.String() == "" {
+		return fmt.Errorf("%w, unknown power control %d", ErrInvalidPowerRequest, int(control))
+	}
+	switch {
+	case state == P_ON && (control == P_MOMPRESS || control == P_RESET || control == P_COLDBOOT):
+		return nil
+	case state == P_OFF && (control == P_MOMPRESS || control == P_PRESSANDHOLD):
+		return nil
+	}
+	return fmt.Errorf("%w, power control %s can't be used to power %s", ErrInvalidPowerRequest, control, state)
+}
 
 // Provides power execution status
+// PowerTask is guarded by a mutex so SubmitPowerState and the
+// PowerExecutor polling loop can safely share it.
 type PowerTask struct {
 	Blade ServerHardware
 	State PowerState // current power state
 	Task
+	Method     rest.Method                      // http method used to submit power changes, rest.PUT or rest.PATCH
+	SettleTime time.Duration                    // time to wait between power off and on in PowerCycle
+	Progress   func(percent int, status string) `json:"-"` // optional, called with the task progress on every check
+	DryRun     bool                             // when true, power requests are planned and logged but never submitted
+	Strict     bool                             // when true, power requests are sent as PowerRequestStrict
+	Refresh    bool                             // when true, an unknown power state triggers a server hardware refresh
+	Plan       *PowerPlan                       `json:"-"` // the last power request planned by a dry run
+	Cache      *PowerStateCache                 `json:"-"` // optional, reuses power state reads, see WithPowerStateCache
+	Guard      *PowerRequestGuard               `json:"-"` // optional, submits a keyed request once, see WithIdempotencyKey
+	// IdempotencyKey - identifies the power request across retries, used with Guard
+	IdempotencyKey string
+	// Conflicts - what to do when another power task runs on the blade, see WithConflictCheck
+	Conflicts ConflictPolicy
+	// Audit - optional, called on every submit and completion, see WithAudit
+	Audit func(AuditEvent) `json:"-"`
+	// ModelTimeout - optional, Timeout for a blade of the given model, read with the
+	// power state, the fixed Timeout is kept when it returns 0, see WithModelTimeouts
+	ModelTimeout func(model string) int `json:"-"`
+	// Steps - optional, called with every new progress update of the power task, see WithProgressSteps
+	Steps func(step ProgressUpdate) `json:"-"`
+	// correlation - correlation id of the power operation running, see rest.WithCorrelationID
+	correlation string
+	// events - channel of the power change running, see Events
+	events chan PowerEvent
+	mu     sync.Mutex
+}
+
+// PowerTaskOption - option for configuring a new PowerTask
+type PowerTaskOption func(*PowerTask)
+
+// WithTimeout - number of task checks before the power task times out
+func WithTimeout(timeout int) PowerTaskOption {
+	return func(pt *PowerTask) { pt.Timeout = timeout }
+}
+
+// WithModelTimeouts - Timeout in checks for blades by model, "ProLiant BL460c Gen9"
+// or its short model "BL460c Gen9", case insensitive, other models keep Timeout
+func WithModelTimeouts(timeouts map[string]int) PowerTaskOption {
+	return WithModelTimeoutFunc(func(model string) int {
+		for m, timeout := range timeouts {
+			if strings.EqualFold(strings.TrimSpace(m), model) {
+				return timeout
+			}
+		}
+		return 0
+	})
+}
+
+// WithModelTimeoutFunc - compute the Timeout in checks from the model of the blade,
+// fn returning 0 keeps Timeout
+func WithModelTimeoutFunc(fn func(model string) int) PowerTaskOption {
+	return func(pt *PowerTask) { pt.ModelTimeout = fn }
+}
+
+// WithWaitTime - time to wait between task checks
+func WithWaitTime(wait time.Duration) PowerTaskOption {
+	return func(pt *PowerTask) { pt.WaitTime = wait }
+}
+
+// WithBackoff - grow the wait time between task checks up to max, no ceiling when max is 0
+func WithBackoff(policy BackoffPolicy, max time.Duration) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Backoff = policy
+		pt.MaxWaitTime = max
+	}
+}
+
+// WithProgress - call fn with the task percent complete and status on every task check
+func WithProgress(fn func(percent int, status string)) PowerTaskOption {
+	return func(pt *PowerTask) { pt.Progress = fn }
+}
+
+// WithProgressSteps - call fn with each progress update of the power task as it appears
+func WithProgressSteps(fn func(step ProgressUpdate)) PowerTaskOption {
+	return func(pt *PowerTask) { pt.Steps = fn }
+}
+
+// WithMethod - http method used to submit power changes, for appliances that expect rest.PATCH
+func WithMethod(m rest.Method) PowerTaskOption {
+	return func(pt *PowerTask) { pt.Method = m }
+}
+
+// WithStrictRequest - always send powerControl, even when empty
+func WithStrictRequest() PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Strict = true
+	}
+}
+
+// WithSoftTimeout - keep extending the timeout, up to max checks, while the power task progresses
+func WithSoftTimeout(max int) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.MaxTimeout = max
+	}
+}
+
+// WithStallChecks - time out once percent complete hasn't changed for checks checks
+func WithStallChecks(checks int) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.StallChecks = checks
+	}
+}
+
+// WithPendingTimeout - allow checks checks of the power task queued, New or Pending on
+// a busy appliance, on top of the Timeout, it fails with ErrTaskPendingTimeout once
+// they are used up and is cancelled when the appliance allows it
+func WithPendingTimeout(checks int) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.PendingTimeout = checks
+	}
+}
+
+// WithJitter - move each wait between checks randomly by up to fraction of WaitTime,
+// so many power tasks started together don't check in lockstep
+func WithJitter(fraction float64) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Jitter = fraction
+	}
+}
+
+// WithRefreshOnUnknown - refresh the server hardware and read the power state again when it's unknown
+func WithRefreshOnUnknown() PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Refresh = true
+	}
+}
+
+// WithDryRun - plan power requests without submitting them, see PowerTask.Plan
+func WithDryRun() PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.DryRun = true
+	}
+}
+
+// WithFailStates - set the task states that fail the power task, see DefaultTaskFailStates
+func WithFailStates(states ...TaskState) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.FailStates = states
+	}
+}
+
+// WithSettleTime - time to wait between power off and on when power cycling
+func WithSettleTime(settle time.Duration) PowerTaskOption {
+	return func(pt *PowerTask) { pt.SettleTime = settle }
+}
+
+// built-in timeout and wait time of a new PowerTask, 36 checks 10 seconds apart,
+// and the least wait time a power change checks its task with
+const (
+	DefaultPowerTimeout     = 36
+	DefaultPowerWaitTime    = 10 * time.Second
+	DefaultMinPowerWaitTime = time.Second
+)
+
+// powerDefaults - timeout and wait time NewPowerTask starts from, see SetDefaultPowerTimeout,
+// and the minimum wait time, see SetMinPowerWaitTime
+var powerDefaults = struct {
+	sync.RWMutex
+	timeout int
+	wait    time.Duration
+	minWait time.Duration
+}{timeout: DefaultPowerTimeout, wait: DefaultPowerWaitTime, minWait: DefaultMinPowerWaitTime}
+
+// SetDefaultPowerTimeout - number of task checks of power tasks created from now on,
+// WithTimeout still overrides it, 0 or less restores DefaultPowerTimeout
+func SetDefaultPowerTimeout(timeout int) {
+	if timeout <= 0 {
+		timeout = DefaultPowerTimeout
+	}
+	powerDefaults.Lock()
+	powerDefaults.timeout = timeout
+	powerDefaults.Unlock()
+}
+
+// SetDefaultPowerWaitTime - time between task checks of power tasks created from now on,
+// WithWaitTime still overrides it, less than 0 restores DefaultPowerWaitTime
+func SetDefaultPowerWaitTime(wait time.Duration) {
+	if wait < 0 {
+		wait = DefaultPowerWaitTime
+	}
+	powerDefaults.Lock()
+	powerDefaults.wait = wait
+	powerDefaults.Unlock()
+}
+
+// SetMinPowerWaitTime - least time between task checks of power changes, so a
+// WaitTime of 0 doesn't poll a shared appliance as fast as the network allows,
+// smaller wait times are raised to it with a warning. 0 turns the minimum off,
+// less than 0 restores DefaultMinPowerWaitTime.
+func SetMinPowerWaitTime(min time.Duration) {
+	if min < 0 {
+		min = DefaultMinPowerWaitTime
+	}
+	powerDefaults.Lock()
+	powerDefaults.minWait = min
+	powerDefaults.Unlock()
+}
+
+// powerTaskDefaults - timeout and wait time of the tasks blade calls wait on,
+// the ones SetDefaultPowerTimeout and SetDefaultPowerWaitTime set
+func powerTaskDefaults() (int, time.Duration) {
+	powerDefaults.RLock()
+	defer powerDefaults.RUnlock()
+	return powerDefaults.timeout, powerDefaults.wait
+}
+
+// clampWaitTime - raise WaitTime to the minimum wait time, and keep MaxWaitTime
+// and Jitter from pulling the waits of GetWaitTime below it, pt.mu held
+func (pt *PowerTask) clampWaitTime() {
+	powerDefaults.RLock()
+	min := powerDefaults.minWait
+	powerDefaults.RUnlock()
+	pt.MinWaitTime = min
+	if pt.WaitTime < min {
+		log.Warnf("Power task wait time %s for %s is below the minimum, using %s.", pt.WaitTime, pt.Blade.Name, min)
+		pt.WaitTime = min
+	}
 }
 
 // Create a new power task manager
 // TODO: refactor PowerTask to use Task vs overloading it here.
-func (pt *PowerTask) NewPowerTask(b ServerHardware) *PowerTask {
+func (pt *PowerTask) NewPowerTask(b ServerHardware, opts ...PowerTaskOption) *PowerTask {
 	pt = &PowerTask{Blade: b,
-		State: P_UKNOWN} //,
+		State: P_UNKNOWN} //,
 	// TaskIsDone:  false,
 	// Client:      b.Client,
 	// URI:         "",
 	// Name:        "",
 	// Owner:       "",
 	// Timeout:     36, // default 6min
-	// WaitTime:    10} // default 10sec, impacts Timeout
+	// WaitTime:    10 * time.Second} // default 10sec, impacts Timeout
 	pt.TaskIsDone = false
 	pt.Client = b.Client
 	pt.URI = ""
 	pt.Name = ""
 	pt.Owner = ""
-	pt.Timeout = 36
-	pt.WaitTime = 10
+	powerDefaults.RLock()
+	pt.Timeout = powerDefaults.timeout
+	pt.WaitTime = powerDefaults.wait
+	powerDefaults.RUnlock()
+	pt.Method = rest.PUT
+	for _, opt := range opts {
+		opt(pt)
+	}
+	pt.clampWaitTime()
+	if !hasClient(b.Client) {
+		// power operations fail with ErrNoBladeClient instead of a nil dereference
+		log.Warnf("Power task for %s, %s created without a Client.", b.Name, b.URI)
+	}
 	return pt
 }
 
-// get current power state
+// NewPowerTaskStrict - NewPowerTask failing right away, instead of on the first
+// power operation, with ErrNoBladeHardware when the blade has no uri and
+// ErrNoBladeClient when it has no Client
+func (pt *PowerTask) NewPowerTaskStrict(b ServerHardware, opts ...PowerTaskOption) (*PowerTask, error) {
+	if b.URI.IsNil() {
+		return nil, fmt.Errorf("%w, %q has no uri", ErrNoBladeHardware, b.Name)
+	}
+	if !hasClient(b.Client) {
+		return nil, fmt.Errorf("%w, for %s", ErrNoBladeClient, b.URI)
+	}
+	return pt.NewPowerTask(b, opts...), nil
+}
+
+// ResetTask - reset the power task back to off
+func (pt *PowerTask) ResetTask() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.Task.ResetTask()
+}
+
+// GetCurrentTaskStatus - Get the current status of the power task
+func (pt *PowerTask) GetCurrentTaskStatus() error {
+	return pt.GetCurrentTaskStatusContext(context.Background())
+}
+
+// GetCurrentTaskStatusContext - Get the current status of the power task, bound to ctx
+func (pt *PowerTask) GetCurrentTaskStatusContext(ctx context.Context) error {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.Task.GetCurrentTaskStatusContext(ctx)
+}
+
+// PowerSnapshot - read only view of the progress of a power task
+type PowerSnapshot struct {
+	State        PowerState // last power state read from the blade
+	Percent      int        // ComputedPercentComplete of the power task
+	TaskState    string     // "Running", "Completed", ...
+	TaskStatus   string     // "Power on Server: se05, bay 16"
+	Done         bool
+	TaskURI      string
+	BladeName    string
+	SerialNumber string
+}
+
+// Snapshot - get the progress of the power task, safe to call while PowerExecutor runs
+func (pt *PowerTask) Snapshot() PowerSnapshot {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return PowerSnapshot{
+		State:        pt.State,
+		Percent:      pt.ComputedPercentComplete,
+		TaskState:    pt.TaskState,
+		TaskStatus:   pt.TaskStatus,
+		Done:         pt.TaskIsDone,
+		TaskURI:      string(pt.URI),
+		BladeName:    pt.Blade.Name,
+		SerialNumber: string(pt.Blade.SerialNumber),
+	}
+}
+
+// String - one line summary of the power task for logs, with %v and %+v
+func (pt *PowerTask) String() string {
+	if pt == nil {
+		return "<nil>"
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	task := "not submitted"
+	if pt.URI != "" {
+		task = fmt.Sprintf("%s %s %d%%", string(pt.URI), pt.TaskState, pt.ComputedPercentComplete)
+	}
+	return fmt.Sprintf("power task %s (%s) state %s, task %s, timeout %d checks of %s",
+		pt.Blade.Name, string(pt.Blade.SerialNumber), powerStateName(pt.State), task, pt.Timeout, pt.WaitTime)
+}
+
+// GoString - summary of the power task for %#v, without the client or blade internals
+func (pt *PowerTask) GoString() string {
+	if pt == nil {
+		return "(*ov.PowerTask)(nil)"
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return fmt.Sprintf("&ov.PowerTask{Blade:%q, SerialNumber:%q, State:%q, TaskURI:%q, TaskState:%q, Percent:%d, TaskIsDone:%t, Timeout:%d, WaitTime:%q}",
+		pt.Blade.Name, string(pt.Blade.SerialNumber), powerStateName(pt.State), string(pt.URI),
+		pt.TaskState, pt.ComputedPercentComplete, pt.TaskIsDone, pt.Timeout, pt.WaitTime.String())
+}
+
+// powerStateName - String of p, without panicking on the zero value
+func powerStateName(p PowerState) string {
+	if p < 1 || int(p) > len(powerstates) {
+		return fmt.Sprintf("PowerState(%d)", int(p))
+	}
+	return p.String()
+}
+
+// getTask - get a copy of the task of the power task
+func (pt *PowerTask) getTask() Task {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.Task
+}
+
+// setTaskIsDone - mark the power task as done
+func (pt *PowerTask) setTaskIsDone() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.TaskIsDone = true
+}
+
+// getState - get the last known power state
+func (pt *PowerTask) getState() PowerState {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.State
+}
+
+// get current power state, reused from the Cache while it is fresh
 func (pt *PowerTask) GetCurrentPowerState() error {
+	return pt.getCurrentPowerState(true)
+}
+
+// getCurrentPowerState - read the power state, from the Cache when cached is
+// set, power operations read the appliance to never act on a stale state
+func (pt *PowerTask) getCurrentPowerState(cached bool) error {
+	pt.mu.Lock()
+	blade, cache := pt.Blade, pt.Cache
+	pt.mu.Unlock()
+	if cached {
+		if b, ok := cache.Get(blade.URI); ok {
+			state, _ := ParsePowerState(b.PowerState)
+			pt.mu.Lock()
+			pt.State = state
+			pt.Blade = b
+			pt.applyModelTimeout()
+			pt.mu.Unlock()
+			return absentPowerState(b)
+		}
+	}
 	// Quick check to make sure we have a proper hardware blade
-	if pt.Blade.URI.IsNil() {
-		pt.State = P_UKNOWN
-		return errors.New("Can't get power on blade without hardware")
+	if blade.URI.IsNil() {
+		pt.mu.Lock()
+		pt.State = P_UNKNOWN
+		pt.mu.Unlock()
+		return ErrNoBladeHardware
+	}
+	if !hasClient(blade.Client) {
+		pt.mu.Lock()
+		pt.State = P_UNKNOWN
+		pt.mu.Unlock()
+		return fmt.Errorf("%w, for %s", ErrNoBladeClient, blade.URI)
 	}
 
 	// get the latest state based on current blade uri
-	b, err := pt.Blade.Client.GetServerHardware(pt.Blade.URI)
+	b, err := blade.Client.GetServerHardware(blade.URI)
 	if err != nil {
-		return err
+		return fmt.Errorf("Error getting server hardware %s: %w", blade.URI, err)
 	}
 	log.Debugf("GetCurrentPowerState() blade -> %+v", b)
 	// Set the current state of the blade as a constant
-	if P_OFF.Equal(b.PowerState) {
-		pt.State = P_OFF
-	} else if P_ON.Equal(b.PowerState) {
-		pt.State = P_ON
-	} else {
+	state, err := ParsePowerState(b.PowerState)
+	pt.mu.Lock()
+	refresh, timeout, wait := pt.Refresh, pt.Timeout, pt.WaitTime
+	pt.mu.Unlock()
+	if state == P_UNKNOWN && refresh {
+		log.Infof("Power state is %s for %s, refreshing server hardware.", b.PowerState, b.Name)
+		if _, rerr := blade.Client.RefreshServerHardware(blade.URI, TaskTimeout(timeout), TaskWaitTime(wait)); rerr != nil {
+			return fmt.Errorf("Error refreshing server hardware %s: %w", blade.URI, rerr)
+		}
+		if b, err = blade.Client.GetServerHardware(blade.URI); err != nil {
+			return fmt.Errorf("Error getting server hardware %s: %w", blade.URI, err)
+		}
+		state, err = ParsePowerState(b.PowerState)
+	}
+	if err != nil {
 		log.Warnf("Un-known power state detected %s, for %s.", b.PowerState, b.Name)
-		pt.State = P_UKNOWN
 	}
+	cache.Put(b)
 	// Reassign the current blade and state of that blade
+	pt.mu.Lock()
+	pt.State = state
 	pt.Blade = b
-	return nil
+	pt.applyModelTimeout()
+	pt.mu.Unlock()
+	return absentPowerState(b)
+}
+
+// applyModelTimeout - set Timeout from ModelTimeout for the model of the blade,
+// by its model or short model, pt.mu must be held
+func (pt *PowerTask) applyModelTimeout() {
+	if pt.ModelTimeout == nil {
+		return
+	}
+	for _, model := range []string{pt.Blade.Model, pt.Blade.ShortModel} {
+		if model == "" {
+			continue
+		}
+		if timeout := pt.ModelTimeout(model); timeout > 0 {
+			pt.Timeout = timeout
+			return
+		}
+	}
+}
+
+// absentPowerState - ErrPowerStateAbsent when the appliance sent no power state,
+// some firmware leaves it out while the blade is in POST
+func absentPowerState(b ServerHardware) error {
+	if strings.TrimSpace(b.PowerState) != "" {
+		return nil
+	}
+	return fmt.Errorf("%w for %s", ErrPowerStateAbsent, b.Name)
 }
 
 // PowerRequest
@@ -135,73 +715,739 @@ type PowerRequest struct {
 	PowerControl string `json:"powerControl,omitempty"`
 }
 
-// TODO: new parameter for submit power state to do P_RESET
+// PowerRequestStrict - PowerRequest that always sends both fields, some
+// models reject a power request that has no powerControl
+type PowerRequestStrict struct {
+	PowerState   string `json:"powerState"`
+	PowerControl string `json:"powerControl"`
+}
 
 // Submit desired power state
 func (pt *PowerTask) SubmitPowerState(s PowerState) {
-	if err := pt.GetCurrentPowerState(); err != nil {
-		pt.TaskIsDone = true
+	pt.SubmitPowerStateWithControl(s, P_MOMPRESS)
+}
+
+// SubmitPowerStateWithControl - submit desired power state using the given power control,
+// for example P_PRESSANDHOLD to force an immediate (hard) shutdown, combinations
+// refused by ValidatePowerRequest are never sent
+func (pt *PowerTask) SubmitPowerStateWithControl(s PowerState, pc PowerControl) {
+	pt.submitPowerState(s, pc)
+}
+
+// PowerPlan - the power request that would be submitted for a blade
+type PowerPlan struct {
+	Method  rest.Method
+	URI     string
+	Request PowerRequest
+	Current PowerState // power state of the blade when planned
+	Desired PowerState
+	Change  bool // false when the blade is already in the desired state
+}
+
+// SubmitPowerStateDryRun - get the power request SubmitPowerState would make
+// without submitting it, the task is marked done
+func (pt *PowerTask) SubmitPowerStateDryRun(s PowerState) (PowerPlan, error) {
+	plan, err := pt.planPowerState(s, P_MOMPRESS)
+	pt.setTaskIsDone()
+	return plan, err
+}
+
+// powerSubmission - result of submitting a power state request
+type powerSubmission struct {
+	URI utils.Nstring // task uri, empty when the desired state was already set
+	Err error
+}
+
+// planPowerState - read the current power state and plan the request for s
+func (pt *PowerTask) planPowerState(s PowerState, pc PowerControl) (PowerPlan, error) {
+	if err := ValidatePowerRequest(s, pc); err != nil {
+		return PowerPlan{Desired: s}, err
+	}
+	// without a reported state the request is sent, as for an unknown state
+	if err := pt.getCurrentPowerState(false); err != nil && !errors.Is(err, ErrPowerStateAbsent) {
 		log.Errorf("Error getting current power state: %s", err)
-		return
+		return PowerPlan{Desired: s}, err
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	// a reset restarts a blade that is on, it is always sent
+	reset := s == P_ON && (pc == P_RESET || pc == P_COLDBOOT)
+	if reset && pt.State != P_ON {
+		return PowerPlan{Current: pt.State, Desired: s}, fmt.Errorf("%w, %s is %s", ErrPowerResetOff, pt.Blade.Name, powerStateName(pt.State))
+	}
+	// a blade mid profile apply or refresh fails the task, refuse a change upfront
+	if s != pt.State || reset {
+		if err := pt.Blade.AcceptsPowerChange(); err != nil {
+			return PowerPlan{Current: pt.State, Desired: s}, err
+		}
+	}
+	method := pt.Method
+	if method == 0 {
+		method = rest.PUT
+	}
+	return PowerPlan{
+		Method:  method,
+		URI:     strings.Join([]string{pt.Blade.URI.String(), "/powerState"}, ""),
+		Request: PowerRequest{PowerState: s.APIValue(), PowerControl: pc.APIValue()},
+		Current: pt.State,
+		Desired: s,
+		Change:  s != pt.State || reset,
+	}, nil
+}
+
+// submitPowerState - submit desired power state, the returned submission carries
+// the task uri to poll or the error that stopped the request
+func (pt *PowerTask) submitPowerState(s PowerState, pc PowerControl) powerSubmission {
+	started := time.Now()
+	sub := pt.sendPowerState(s, pc)
+	observePowerSubmission(s, sub)
+	pt.audit(A_SUBMIT, s, pc, started, sub.Err)
+	return sub
+}
+
+// sendPowerState - plan and send the power request for submitPowerState
+func (pt *PowerTask) sendPowerState(s PowerState, pc PowerControl) powerSubmission {
+	plan, err := pt.planPowerState(s, pc)
+	if err != nil {
+		pt.setTaskIsDone()
+		return powerSubmission{Err: err}
+	}
+	pt.mu.Lock()
+	blade, dryrun, strict, cache, id := pt.Blade, pt.DryRun, pt.Strict, pt.Cache, pt.correlation
+	if dryrun {
+		pt.Plan = &plan
 	}
-	if s != pt.State {
-		log.Infof("Powering %s server %s for %s.", s, pt.Blade.Name, pt.Blade.SerialNumber)
-		var (
-			body = PowerRequest{PowerState: s.String(), PowerControl: P_MOMPRESS.String()}
-			uri  = strings.Join([]string{pt.Blade.URI.String(),
-				"/powerState"}, "")
-		)
-		log.Debugf("REST : %s \n %+v\n", uri, body)
-		log.Debugf("pt -> %+v", pt)
-		data, err := pt.Blade.Client.RestAPICall(rest.PUT, uri, body)
+	pt.mu.Unlock()
+	plog := logWith(powerLogFields(id, blade, s))
+	if dryrun {
+		plog.Infof("[%s] Dry run, power %s for server %s would %s %s %+v, change %t.", id, s, blade.Name, plan.Method, plan.URI, plan.Request, plan.Change)
+		pt.setTaskIsDone()
+		return powerSubmission{}
+	}
+	pt.mu.Lock()
+	guard, key := pt.Guard, pt.IdempotencyKey
+	pt.mu.Unlock()
+	// the key is claimed before sending, a retry or a concurrent request with the
+	// same key never sends a second momentary press
+	task, claimed, err := guard.reserve(key, blade.URI, s)
+	if err != nil {
+		pt.setTaskIsDone()
+		return powerSubmission{Err: err}
+	}
+	if !claimed && task != "" {
+		plog.Infof("[%s] Power %s request %s already submitted for %s, polling task %s.", id, s, key, blade.Name, task)
+		pt.mu.Lock()
+		pt.URI = task
+		pt.mu.Unlock()
+		return powerSubmission{URI: task}
+	}
+	if !plan.Change {
+		if claimed {
+			guard.Forget(key)
+		}
+		plog.Infof("[%s] Desired Power State already set -> %s", id, plan.Current)
+		pt.setTaskIsDone()
+		return powerSubmission{}
+	}
+	if !claimed {
+		pt.setTaskIsDone()
+		return powerSubmission{Err: fmt.Errorf("%w, %s for %s", ErrPowerRequestUnconfirmed, key, blade.Name)}
+	}
+	pt.mu.Lock()
+	conflicts := pt.Conflicts
+	pt.mu.Unlock()
+	waited, err := pt.checkConflicts(conflicts)
+	if err == nil && waited {
+		// the task waited on may have set the desired state already
+		plan, err = pt.planPowerState(s, pc)
+	}
+	if err != nil || !plan.Change {
+		guard.Forget(key)
+		pt.setTaskIsDone()
 		if err != nil {
-			pt.TaskIsDone = true
-			log.Errorf("Error with power state request: %s", err)
-			return
+			return powerSubmission{Err: err}
 		}
+		plog.Infof("[%s] Desired Power State already set -> %s", id, plan.Current)
+		return powerSubmission{}
+	}
 
-		log.Debugf("SubmitPowerState %s", data)
-		if err := json.Unmarshal([]byte(data), &pt); err != nil {
-			pt.TaskIsDone = true
-			log.Errorf("Error with power state un-marshal: %s", err)
-			return
+	plog.Infof("[%s] Powering %s server %s, %s.", id, s, blade.Name, blade.SerialNumber)
+	var body interface{} = plan.Request
+	if strict {
+		body = PowerRequestStrict(plan.Request)
+	}
+	log.Debugf("[%s] REST : %s %s \n %+v\n", id, plan.Method, plan.URI, body)
+	// the request is never abandoned once sent, only its correlation id is passed on
+	data, err := blade.Client.RestAPICallContext(rest.WithCorrelationID(context.Background(), id), plan.Method, plan.URI, body)
+	if err != nil {
+		// the answer may be lost after the appliance applied the request, a momentary
+		// press sent again would toggle the blade back, the key stays claimed
+		transient := isTransientError(err)
+		if transient && pt.powerStateApplied(s) {
+			plog.Warnf("[%s] Power %s request for %s failed with %s, the blade is already %s, not resubmitting.", id, s, blade.Name, err, pt.getState())
+			pt.setTaskIsDone()
+			return powerSubmission{}
 		}
-	} else {
-		log.Infof("Desired Power State already set -> %s", pt.State)
+		if !transient {
+			// the appliance refused the request, nothing was applied
+			guard.Forget(key)
+		}
+		pt.setTaskIsDone()
+		plog.Errorf("[%s] Error with power state request: %s", id, err)
+		return powerSubmission{Err: fmt.Errorf("Error with power state request: %w", err)}
+	}
+
+	// the power state is changing, readers of the cache must go to the appliance
+	cache.Invalidate(blade.URI)
+	log.Debugf("[%s] SubmitPowerState %s", id, data)
+	if err := rest.CheckJSON(data); err != nil {
+		pt.setTaskIsDone()
+		plog.Errorf("[%s] Error with power state response: %s", id, err)
+		return powerSubmission{Err: err}
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if err := json.Unmarshal([]byte(data), &pt.Task); err != nil {
 		pt.TaskIsDone = true
+		plog.Errorf("[%s] Error with power state un-marshal: %s", id, err)
+		return powerSubmission{Err: err}
 	}
+	guard.confirm(key, pt.URI)
+	return powerSubmission{URI: pt.URI}
+}
 
-	return
+// powerStateApplied - read the blade again, true when it is in s or changing to s
+func (pt *PowerTask) powerStateApplied(s PowerState) bool {
+	if err := pt.getCurrentPowerState(false); err != nil {
+		return false
+	}
+	switch state := pt.getState(); {
+	case state == s:
+		return true
+	case s == P_ON:
+		return state == P_POWERINGON || state == P_RESETTING
+	case s == P_OFF:
+		return state == P_POWERINGOFF
+	}
+	return false
+}
+
+// isTransientError - true when err may hide a request the appliance applied, a
+// busy appliance answering 5xx or a transport error losing the answer
+func isTransientError(err error) bool {
+	var serr *rest.StatusError
+	if errors.As(err, &serr) {
+		return serr.Temporary()
+	}
+	return !errors.Is(err, context.Canceled)
 }
 
 // Submit desired power state and wait
 // Most of our concurrency will happen in PowerExecutor
-func (pt *PowerTask) PowerExecutor(s PowerState) error {
-	currenttime := 0
-	pt.State = P_UKNOWN
+// returns the verified power state of the blade once the task is done
+func (pt *PowerTask) PowerExecutor(s PowerState) (PowerState, error) {
+	return pt.PowerExecutorWithControl(s, P_MOMPRESS)
+}
+
+// PowerExecutorWithControl - submit desired power state with the given power control and wait
+func (pt *PowerTask) PowerExecutorWithControl(s PowerState, pc PowerControl) (PowerState, error) {
+	return pt.powerExecutor(context.Background(), s, pc)
+}
+
+// PowerExecutorContext - submit desired power state and wait, returns ctx.Err()
+// as soon as the context is cancelled or its deadline passes
+func (pt *PowerTask) PowerExecutorContext(ctx context.Context, s PowerState) (PowerState, error) {
+	return pt.powerExecutor(ctx, s, P_MOMPRESS)
+}
+
+// powerExecutor - submit desired power state and wait until done, timeout or ctx is done
+func (pt *PowerTask) powerExecutor(ctx context.Context, s PowerState, pc PowerControl) (PowerState, error) {
+	starttime := time.Now()
+	state, err := pt.executePowerState(ctx, s, pc, starttime)
+	observePowerOperation(s, starttime, err)
+	pt.audit(A_COMPLETE, s, pc, starttime, err)
+	if err != nil {
+		pt.emit(PowerEvent{Type: E_FAILED, Err: err})
+	} else {
+		pt.emit(PowerEvent{Type: E_COMPLETED})
+	}
+	pt.closeEvents()
+	return state, err
+}
+
+// powerLogFields - structured log keys of the power change of blade to s,
+// see logWith
+func powerLogFields(id string, blade ServerHardware, s PowerState) rest.Fields {
+	return rest.Fields{"correlation": id, "blade": blade.Name, "serial": blade.SerialNumber.String(), "state": s.APIValue()}
+}
+
+// taskLogFields - fields with the uri and percent complete of task t
+func taskLogFields(fields rest.Fields, t Task) rest.Fields {
+	merged := rest.Fields{"task": t.URI.String(), "percent": t.ComputedPercentComplete}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// correlate - returns ctx carrying the correlation id of a power operation, the
+// one on ctx, else the one of the blade client, else a new one, and keeps it for
+// the power request
+func (pt *PowerTask) correlate(ctx context.Context) context.Context {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if rest.CorrelationID(ctx) == "" {
+		if c, ok := pt.Blade.Client.(*OVClient); ok && c != nil {
+			ctx = c.CorrelationContext(ctx)
+		} else {
+			ctx = rest.WithCorrelationID(ctx, rest.NewCorrelationID())
+		}
+	}
+	pt.correlation = rest.CorrelationID(ctx)
+	return ctx
+}
+
+// executePowerState - submit and wait for powerExecutor, started at starttime
+func (pt *PowerTask) executePowerState(ctx context.Context, s PowerState, pc PowerControl, starttime time.Time) (PowerState, error) {
+	if err := ctx.Err(); err != nil {
+		return pt.getState(), err
+	}
+	ctx = pt.correlate(ctx)
+	id := rest.CorrelationID(ctx)
+	pt.mu.Lock()
+	pt.State = P_UNKNOWN
+	// WaitTime may have been set after NewPowerTask
+	pt.clampWaitTime()
+	pt.mu.Unlock()
 	pt.ResetTask()
-	go pt.SubmitPowerState(s)
-	for !pt.TaskIsDone && (currenttime < pt.Timeout) {
-		if err := pt.GetCurrentTaskStatus(); err != nil {
-			return err
+
+	// wait on the power request to be accepted before polling the task
+	submitted := make(chan powerSubmission, 1)
+	go func() {
+		submitted <- pt.submitPowerState(s, pc)
+	}()
+	select {
+	case <-ctx.Done():
+		// cancel the task once the request in flight is accepted
+		go func() {
+			if sub := <-submitted; sub.URI != "" {
+				pt.abandon()
+			}
+		}()
+		return pt.getState(), ctx.Err()
+	case sub := <-submitted:
+		if sub.Err != nil {
+			return pt.getState(), sub.Err
 		}
-		if pt.URI != "" && T_COMPLETED.Equal(pt.TaskState) {
-			pt.TaskIsDone = true
+		log.Debugf("[%s] Power %s state submitted, task %s", id, s, sub.URI)
+		if sub.URI != "" {
+			pt.emit(PowerEvent{Type: E_SUBMITTED, TaskURI: sub.URI})
 		}
-		if pt.URI != "" {
-			log.Debugf("Waiting to set power state %s for blade %s, %s", s, pt.Blade.Name)
-			log.Infof("Working on power state,%d%%, %s.", pt.ComputedPercentComplete, pt.TaskStatus)
+	}
+
+	pt.mu.Lock()
+	name, dryrun, fields := pt.Blade.Name, pt.DryRun, powerLogFields(id, pt.Blade, s)
+	pt.mu.Unlock()
+	plog := logWith(fields)
+	seen, running, last := 0, T_RUNNING.Equal(pt.getTask().TaskState), pt.getState()
+	if running {
+		pt.emit(PowerEvent{Type: E_RUNNING})
+	}
+	currenttime, timeout, err := pollTask(ctx, pt, pt.Timeout, func(t Task) {
+		observer.IncCounter(MetricPowerPolls, map[string]string{"state": s.APIValue()})
+		if !running && T_RUNNING.Equal(t.TaskState) {
+			running = true
+			pt.emit(PowerEvent{Type: E_RUNNING})
+		}
+		if t.URI != "" {
+			log.Debugf("[%s] Waiting to set power state %s for blade %s, %s", id, s, name, t.URI)
+			tlog := logWith(taskLogFields(fields, t))
+			tlog.Infof("[%s] Working on power state, %d%%, %s.", id, t.ComputedPercentComplete, t.TaskStatus)
+			if pt.Progress != nil {
+				pt.Progress(t.ComputedPercentComplete, t.TaskStatus)
+			}
+			for _, step := range t.ProgressUpdatesSince(seen) {
+				tlog.Infof("[%s] Power %s step for %s, %s", id, s, name, step.StatusUpdate)
+				if pt.Steps != nil {
+					pt.Steps(step)
+				}
+			}
+			if len(t.ProgressUpdates) > seen {
+				seen = len(t.ProgressUpdates)
+			}
 		} else {
-			log.Info("Working on power state.")
+			plog.Infof("[%s] Working on power state.", id)
+		}
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			plog.Warnf("[%s] Power %s state cancelled for %s: %s", id, s, name, ctx.Err())
+			go pt.abandon()
+		} else if errors.Is(err, ErrTaskFailed) {
+			plog.Warnf("[%s] Power %s state task failed for %s: %s", id, s, name, err)
+		} else if errors.Is(err, ErrTaskPendingTimeout) {
+			// the queued request would still run whenever the appliance gets to it
+			plog.Warnf("[%s] Power %s state task never started for %s, appliance busy: %s", id, s, name, err)
+			go pt.abandon()
+		}
+		return pt.getState(), err
+	}
+	// verify the state the blade actually ended up in, waiting out any power transition,
+	// a task that completed on its last check still gets its state verified
+	if pt.getTask().TaskIsDone && currenttime >= timeout {
+		timeout = currenttime + 1
+	}
+	for currenttime < timeout {
+		err := pt.getCurrentPowerState(false)
+		if err != nil && !errors.Is(err, ErrPowerStateAbsent) {
+			return pt.getState(), err
+		}
+		state := pt.getState()
+		if state != last {
+			pt.emit(PowerEvent{Type: E_STATE, State: state, Previous: last})
+			last = state
+		}
+		if err == nil && !state.IsTransitional() {
+			// a momentary press toggles, a double press leaves the blade where it started
+			if state != s && !dryrun {
+				plog.Warnf("[%s] Power %s state task completed for %s, but the blade is %s.", id, s, name, state)
+				return state, fmt.Errorf("Power %s failed for %s, current power state is %s: %w", s, name, state, ErrPowerStateMismatch)
+			}
+			plog.Infof("[%s] Power Task Execution Completed", id)
+			return state, nil
+		}
+		if err != nil {
+			plog.Infof("[%s] Waiting on power state, %s.", id, err)
+		} else {
+			plog.Infof("[%s] Waiting on power transition, %s.", id, state)
+		}
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-time.After(pt.GetWaitTime(currenttime)):
 		}
-
-		// wait time before next check
-		time.Sleep(time.Millisecond * (1000 * pt.WaitTime)) // wait 10sec before checking the status again
 		currenttime++
 	}
-	if !(currenttime < pt.Timeout) {
-		log.Warnf("Power %s state timed out for %s.", s, pt.Blade.Name)
+	plog.Warnf("[%s] Power %s state timed out for %s.", id, s, name)
+	log.Debugf("[%s] pt -> %+v", id, pt)
+	return pt.getState(), &PowerTimeoutError{State: s, Blade: name, Elapsed: time.Since(starttime)}
+}
+
+// WaitForPowerAndHealth - power on the blade, then wait for its hardware status to
+// reach OK, checking up to Timeout times. The error says which condition failed.
+func (pt *PowerTask) WaitForPowerAndHealth() (PowerState, error) {
+	return pt.WaitForPowerAndHealthContext(context.Background())
+}
+
+// WaitForPowerAndHealthContext - WaitForPowerAndHealth returning ctx.Err() as soon
+// as the context is cancelled or its deadline passes
+func (pt *PowerTask) WaitForPowerAndHealthContext(ctx context.Context) (PowerState, error) {
+	state, err := pt.PowerExecutorContext(ctx, P_ON)
+	if err != nil {
+		return state, err
 	}
-	log.Infof("Power Task Execution Completed")
-	return nil
+	pt.mu.Lock()
+	pt.clampWaitTime()
+	blade, timeout := pt.Blade, pt.Timeout
+	pt.mu.Unlock()
+	if state != P_ON {
+		return state, fmt.Errorf("Power on failed for %s, current power state is %s: %w", blade.Name, state, ErrPowerStateMismatch)
+	}
+	status := blade.Status
+	for check := 0; check < timeout; check++ {
+		if err := ctx.Err(); err != nil {
+			return state, err
+		}
+		b, err := blade.Client.GetServerHardware(blade.URI)
+		if err != nil {
+			return state, fmt.Errorf("Error getting server hardware %s: %w", blade.URI, err)
+		}
+		pt.mu.Lock()
+		pt.Blade = b
+		pt.mu.Unlock()
+		status = b.Status
+		if strings.EqualFold(strings.TrimSpace(status), "OK") {
+			log.Infof("Blade %s is powered on with hardware status %s.", b.Name, status)
+			return state, nil
+		}
+		log.Infof("Waiting on hardware status for %s, %s.", b.Name, status)
+		pt.mu.Lock()
+		wait := pt.GetWaitTime(check)
+		pt.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return state, fmt.Errorf("%w for %s, powered on but hardware status is %s after %d checks", ErrHardwareNotHealthy, blade.Name, status, timeout)
+}
+
+// WaitForPowerState - wait for the blade to report power state s, reading it from the
+// appliance up to Timeout times without any task, for appliances with flaky task
+// reporting or a lost task uri. Returns the last observed state, a PowerTimeoutError
+// when s isn't reached in time.
+func (pt *PowerTask) WaitForPowerState(s PowerState) (PowerState, error) {
+	return pt.WaitForPowerStateContext(context.Background(), s)
+}
+
+// WaitForPowerStateContext - wait for the blade to report power state s, returns
+// ctx.Err() as soon as the context is cancelled or its deadline passes
+func (pt *PowerTask) WaitForPowerStateContext(ctx context.Context, s PowerState) (PowerState, error) {
+	starttime := time.Now()
+	pt.mu.Lock()
+	pt.clampWaitTime()
+	name, timeout := pt.Blade.Name, pt.Timeout
+	pt.mu.Unlock()
+	for check := 0; check < timeout; check++ {
+		if err := ctx.Err(); err != nil {
+			return pt.getState(), err
+		}
+		err := pt.getCurrentPowerState(false)
+		if err != nil && !errors.Is(err, ErrPowerStateAbsent) {
+			return pt.getState(), err
+		}
+		state := pt.getState()
+		if err == nil && state == s {
+			log.Infof("Blade %s reached power state %s.", name, s)
+			return state, nil
+		}
+		log.Infof("Waiting on power state %s for %s, %s.", s, name, state)
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-time.After(pt.GetWaitTime(check)):
+		}
+	}
+	log.Warnf("Power %s state not reached for %s, last state %s.", s, name, pt.getState())
+	return pt.getState(), &PowerTimeoutError{State: s, Blade: name, Elapsed: time.Since(starttime)}
+}
+
+// DefaultPowerConcurrency - blades powered at the same time by PowerExecutorBulk
+// when maxConcurrency is not set
+const DefaultPowerConcurrency = 8
+
+// PowerResult - the outcome of a power change on one blade
+type PowerResult struct {
+	Blade          ServerHardware
+	RequestedState PowerState
+	FinalState     PowerState // power state the blade was left in
+	Err            error
+	Duration       time.Duration // time spent powering the blade
+}
+
+// PowerResults - the outcomes of a bulk power change, in the order of the blades
+type PowerResults []PowerResult
+
+// Failed - the results that have an error
+func (r PowerResults) Failed() PowerResults {
+	var failed PowerResults
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// PowerExecutorBulk - set the power state s on all blades, running at most maxConcurrency
+// power tasks at a time, a failed blade does not stop the others. Results are in the
+// order of blades, the error joins the errors of all blades that failed. Pass
+// WithConflictCheck to fail or wait on power tasks other callers run on the blades.
+func (c *OVClient) PowerExecutorBulk(blades []ServerHardware, s PowerState, maxConcurrency int, opts ...PowerTaskOption) (PowerResults, error) {
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultPowerConcurrency
+	}
+	// the power tasks share their task checks unless given a batcher
+	batcher := c.NewTaskBatcher(DefaultTaskBatchWindow)
+	var (
+		results = make(PowerResults, len(blades))
+		work    = make(chan int)
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < maxConcurrency && w < len(blades); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = c.powerBlade(blades[i], s, batcher, opts...)
+			}
+		}()
+	}
+	for i := range blades {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	var errs []error
+	for _, r := range results.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", r.Blade.Name, r.Err))
+	}
+	if len(errs) > 0 {
+		log.Warnf("Power %s state failed for %d of %d blades", s, len(errs), len(blades))
+	}
+	return results, errors.Join(errs...)
+}
+
+// powerBlade - set the power state of a single blade for PowerExecutorBulk, each blade
+// gets its own Clone of an *OVClient so session and header updates aren't shared,
+// other ServerHardwareClient implementations are used as they are. Blades on an
+// *OVClient check their task through batcher unless the options gave a batcher.
+func (c *OVClient) powerBlade(b ServerHardware, s PowerState, batcher *TaskBatcher, opts ...PowerTaskOption) PowerResult {
+	b, batched := c.cloneBladeClient(b)
+	start := time.Now()
+	var pt *PowerTask
+	pt = pt.NewPowerTask(b, opts...)
+	if batched && pt.Batcher == nil && batcher != nil {
+		batcher.limitWindow(pt.WaitTime)
+		pt.Batcher = batcher
+	}
+	state, err := pt.PowerExecutor(s)
+	return PowerResult{Blade: pt.Blade, RequestedState: s, FinalState: state, Err: err, Duration: time.Since(start)}
+}
+
+// cloneBladeClient - b with its own Clone of an *OVClient, c when it has none, true
+// when the client was cloned, other ServerHardwareClient implementations are kept
+func (c *OVClient) cloneBladeClient(b ServerHardware) (ServerHardware, bool) {
+	switch client := b.Client.(type) {
+	case nil:
+		b.Client = c.Clone()
+		return b, true
+	case *OVClient:
+		if client == nil {
+			client = c
+		}
+		b.Client = client.Clone()
+		return b, true
+	}
+	return b, false
+}
+
+// PowerOn - power on the blade, same as PowerExecutor(P_ON)
+func (pt *PowerTask) PowerOn() (PowerState, error) {
+	return pt.PowerExecutor(P_ON)
+}
+
+// PowerOff - gracefully power off the blade, same as PowerExecutor(P_OFF)
+func (pt *PowerTask) PowerOff() (PowerState, error) {
+	return pt.PowerExecutor(P_OFF)
+}
+
+// PowerStatus - read the current power state of the blade, same as GetCurrentPowerState
+func (pt *PowerTask) PowerStatus() (PowerState, error) {
+	err := pt.GetCurrentPowerState()
+	return pt.getState(), err
+}
+
+// Reboot - orderly reset of a blade that is on with the Reset power control, power
+// isn't removed as in PowerCycle. Fails with ErrPowerResetOff when the blade isn't on.
+func (pt *PowerTask) Reboot() (PowerState, error) {
+	state, err := pt.PowerExecutorWithControl(P_ON, P_RESET)
+	if err != nil {
+		return state, err
+	}
+	if state != P_ON {
+		return state, fmt.Errorf("Reboot failed for %s, current power state is %s: %w", pt.Blade.Name, state, ErrPowerStateMismatch)
+	}
+	return state, nil
+}
+
+// PowerCycle - power off the blade, wait for SettleTime, then power it back on
+// each phase is verified and honors the Timeout and WaitTime of the power task
+func (pt *PowerTask) PowerCycle() (PowerState, error) {
+	state, err := pt.PowerExecutor(P_OFF)
+	if err != nil {
+		return state, err
+	}
+	if state != P_OFF {
+		return state, fmt.Errorf("Power cycle failed to power off %s, current power state is %s: %w", pt.Blade.Name, state, ErrPowerStateMismatch)
+	}
+	if pt.SettleTime > 0 {
+		log.Debugf("Waiting %s for %s to settle before power on", pt.SettleTime, pt.Blade.Name)
+		time.Sleep(pt.SettleTime)
+	}
+	state, err = pt.PowerExecutor(P_ON)
+	if err != nil {
+		return state, err
+	}
+	if state != P_ON {
+		return state, fmt.Errorf("Power cycle failed to power on %s, current power state is %s: %w", pt.Blade.Name, state, ErrPowerStateMismatch)
+	}
+	return state, nil
+}
+
+// EnsurePowerState - bring all blades to the desired power state, submitting only for
+// blades not already in it, and wait with WaitForPowerState until every blade reports
+// it or timeout elapses, no limit but the Timeout of the power tasks when 0. The map
+// has the error of each blade that failed, by blade uri, the error joins them.
+func (c *OVClient) EnsurePowerState(blades []ServerHardware, desired PowerState, timeout time.Duration, opts ...PowerTaskOption) (map[string]error, error) {
+	if err := ValidatePowerRequest(desired, P_MOMPRESS); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var (
+		errs  = make([]error, len(blades))
+		work  = make(chan int)
+		wg    sync.WaitGroup
+		start = time.Now()
+	)
+	for w := 0; w < DefaultPowerConcurrency && w < len(blades); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				errs[i] = c.ensureBladePowerState(ctx, blades[i], desired, timeout > 0, start, opts...)
+			}
+		}()
+	}
+	for i := range blades {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	var (
+		failed = make(map[string]error)
+		joined []error
+	)
+	for i, err := range errs {
+		if err != nil {
+			failed[blades[i].URI.String()] = err
+			joined = append(joined, fmt.Errorf("%s: %w", blades[i].Name, err))
+		}
+	}
+	if len(joined) > 0 {
+		log.Warnf("Power %s state not ensured for %d of %d blades", desired, len(joined), len(blades))
+	}
+	return failed, errors.Join(joined...)
+}
+
+// ensureBladePowerState - submit desired for one blade of EnsurePowerState when it isn't
+// in it and wait for it, only ctx bounds the wait when bounded is set
+func (c *OVClient) ensureBladePowerState(ctx context.Context, b ServerHardware, desired PowerState, bounded bool, start time.Time, opts ...PowerTaskOption) error {
+	b, _ = c.cloneBladeClient(b)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(b, opts...)
+	if bounded {
+		pt.Timeout = math.MaxInt32
+	}
+	if err := pt.getCurrentPowerState(false); err != nil && !errors.Is(err, ErrPowerStateAbsent) {
+		return err
+	}
+	if pt.getState() != desired {
+		if sub := pt.submitPowerState(desired, P_MOMPRESS); sub.Err != nil {
+			return sub.Err
+		}
+	}
+	_, err := pt.WaitForPowerStateContext(ctx, desired)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &PowerTimeoutError{State: desired, Blade: b.Name, Elapsed: time.Since(start)}
+	}
+	return err
 }
@@ -0,0 +1,99 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// PowerStateCache - server hardware read by GetCurrentPowerState kept for TTL,
+// keyed by blade uri, share one between power tasks to poll many blades cheaply.
+// Power operations always read the appliance and refresh the cache.
+type PowerStateCache struct {
+	TTL time.Duration // time a read is reused, 0 disables caching
+
+	mu      sync.Mutex
+	entries map[utils.Nstring]powerStateEntry
+}
+
+type powerStateEntry struct {
+	blade   ServerHardware
+	fetched time.Time
+}
+
+// NewPowerStateCache - get a cache that reuses reads for ttl
+func NewPowerStateCache(ttl time.Duration) *PowerStateCache {
+	return &PowerStateCache{TTL: ttl, entries: make(map[utils.Nstring]powerStateEntry)}
+}
+
+// Get - get the cached server hardware for uri, false when missing or older than TTL
+func (c *PowerStateCache) Get(uri utils.Nstring) (ServerHardware, bool) {
+	if c == nil {
+		return ServerHardware{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[uri]
+	if !ok || c.TTL <= 0 || time.Since(e.fetched) >= c.TTL {
+		return ServerHardware{}, false
+	}
+	return e.blade, true
+}
+
+// Put - cache a server hardware read now
+func (c *PowerStateCache) Put(b ServerHardware) {
+	if c == nil || b.URI.IsNil() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[utils.Nstring]powerStateEntry)
+	}
+	c.entries[b.URI] = powerStateEntry{blade: b, fetched: time.Now()}
+}
+
+// Invalidate - drop the cached read for uri, the next read goes to the appliance
+func (c *PowerStateCache) Invalidate(uri utils.Nstring) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, uri)
+}
+
+// Clear - drop all cached reads
+func (c *PowerStateCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[utils.Nstring]powerStateEntry)
+}
+
+// WithPowerStateCache - reuse GetCurrentPowerState reads from cache, see PowerStateCache
+func WithPowerStateCache(cache *PowerStateCache) PowerTaskOption {
+	return func(pt *PowerTask) {
+		pt.Cache = cache
+	}
+}
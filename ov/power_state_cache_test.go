@@ -0,0 +1,69 @@
+package ov
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// countGets - number of reads of uri made to the fake
+func countGets(f *ovtest.Fake, uri string) (n int) {
+	for _, call := range f.Calls() {
+		if call.Method == rest.GET && call.Path == uri {
+			n++
+		}
+	}
+	return n
+}
+
+// power state cache test, reads are reused for the ttl and power operations stay fresh
+func TestPowerStateCache(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	cache := NewPowerStateCache(time.Hour)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithPowerStateCache(cache))
+	before := countGets(f, b.URI)
+	for i := 0; i < 5; i++ {
+		state, err := pt.PowerStatus()
+		assert.NoError(t, err, "PowerStatus threw error -> %s", err)
+		assert.Equal(t, P_OFF, state)
+	}
+	assert.Equal(t, 1, countGets(f, b.URI)-before)
+
+	// changed behind the cache's back, power operations still see it
+	b.State = "On"
+	state, err := pt.PowerStatus()
+	assert.NoError(t, err, "PowerStatus threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	state, err = pt.PowerExecutor(P_OFF)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, []string{"Off"}, b.PowerRequests())
+	cached, ok := cache.Get(blade.URI)
+	assert.True(t, ok)
+	assert.Equal(t, "Off", cached.PowerState)
+
+	cache.Invalidate(blade.URI)
+	_, ok = cache.Get(blade.URI)
+	assert.False(t, ok)
+	cache.Put(blade)
+	cache.Clear()
+	_, ok = cache.Get(blade.URI)
+	assert.False(t, ok)
+
+	// no ttl, nothing is reused
+	cache = NewPowerStateCache(0)
+	pt = pt.NewPowerTask(blade, WithPowerStateCache(cache))
+	before = countGets(f, b.URI)
+	pt.PowerStatus()
+	pt.PowerStatus()
+	assert.Equal(t, 2, countGets(f, b.URI)-before)
+}
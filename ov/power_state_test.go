@@ -1,14 +1,29 @@
 package ov
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
+// TestMain - the fake appliance answers right away, power tests check their tasks
+// without the minimum wait time
+func TestMain(m *testing.M) {
+	SetMinPowerWaitTime(0)
+	os.Exit(m.Run())
+}
+
 // testing power state type
 func TestPowerState(t *testing.T) {
 	var (
@@ -35,19 +50,1066 @@ func TestPowerState(t *testing.T) {
 		assert.Equal(t, expectsData, pt.Blade.SerialNumber.String())
 
 		// Test the power state executor to off
-		log.Info("------- Setting Power to Off")
-		err = pt.PowerExecutor(P_OFF)
+		log.Infof("------- Setting Power to Off")
+		state, err := pt.PowerExecutor(P_OFF)
 		assert.NoError(t, err, "PowerExecutor threw no errors -> %s", err)
+		assert.Equal(t, P_OFF, state)
 
 		// Test the power state executor to on
-		log.Info("------- Setting Power to On")
-		err = pt.PowerExecutor(P_ON)
+		log.Infof("------- Setting Power to On")
+		state, err = pt.PowerExecutor(P_ON)
 		assert.NoError(t, err, "PowerExecutor threw no errors -> %s", err)
+		assert.Equal(t, P_ON, state)
 
 		// Test the power state executor to off and leave off
-		log.Info("------- Setting Power to Off")
-		err = pt.PowerExecutor(P_OFF)
+		log.Infof("------- Setting Power to Off")
+		state, err = pt.PowerExecutor(P_OFF)
 		assert.NoError(t, err, "PowerExecutor threw no errors -> %s", err)
+		assert.Equal(t, P_OFF, state)
+
+	}
+}
+
+// TestPowerControl verify power control strings
+func TestPowerControl(t *testing.T) {
+	assert.Equal(t, "ColdBoot", P_COLDBOOT.String())
+	assert.Equal(t, "MomentaryPress", P_MOMPRESS.String())
+	assert.Equal(t, "Reset", P_RESET.String())
+	assert.Equal(t, "PressAndHold", P_PRESSANDHOLD.String())
+}
+
+// TestPowerExecutorContextCancelled verify a cancelled context returns before submitting
+func TestPowerExecutorContextCancelled(t *testing.T) {
+	var pt *PowerTask
+	pt = pt.NewPowerTask(ServerHardware{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := pt.PowerExecutorContext(ctx, P_ON)
+	assert.Equal(t, context.Canceled, err)
+}
+
+// TestPowerTimeoutError verify the timeout error message
+func TestPowerTimeoutError(t *testing.T) {
+	var err error
+	err = &PowerTimeoutError{State: P_ON, Blade: "se05, bay 16", Elapsed: 6 * time.Minute}
+	assert.Equal(t, "Power On state timed out for se05, bay 16 after 6m0s.", err.Error())
+	assert.True(t, errors.Is(err, ErrPowerTimeout))
+
+	var timeout *PowerTimeoutError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &timeout))
+	assert.Equal(t, P_ON, timeout.State)
+}
+
+// getTestPowerBlade - get a blade of a fake appliance in the given power state
+func getTestPowerBlade(t *testing.T, state string) (ServerHardware, *ovtest.Blade) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "fake, bay 1", state)
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	return blade, b
+}
+
+// TestPowerExecutorConcurrency exercise submit and polling sharing a PowerTask, run with -race
+func TestPowerExecutorConcurrency(t *testing.T) {
+	blade, _ := getTestPowerBlade(t, "Off")
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithTimeout(5), WithWaitTime(0))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+}
+
+// TestPowerExecutorSubmitError verify a failed submission is returned before polling
+func TestPowerExecutorSubmitError(t *testing.T) {
+	var pt *PowerTask
+	pt = pt.NewPowerTask(ServerHardware{})
+	state, err := pt.PowerExecutor(P_ON)
+	assert.True(t, errors.Is(err, ErrNoBladeHardware), "expected ErrNoBladeHardware, got %s", err)
+	assert.Equal(t, P_UNKNOWN, state)
+}
+
+// TestNewPowerTaskOptions verify defaults and options for a new power task
+func TestNewPowerTaskOptions(t *testing.T) {
+	var pt *PowerTask
+	pt = pt.NewPowerTask(ServerHardware{})
+	assert.Equal(t, 36, pt.Timeout)
+	assert.Equal(t, 10*time.Second, pt.WaitTime)
+
+	pt = pt.NewPowerTask(ServerHardware{}, WithTimeout(90), WithWaitTime(5*time.Second))
+	assert.Equal(t, 90, pt.Timeout)
+	assert.Equal(t, 5*time.Second, pt.WaitTime)
+}
+
+// TestPowerExecutorWaitTime verify the executor sleeps the configured WaitTime between checks
+func TestPowerExecutorWaitTime(t *testing.T) {
+	blade, _ := getTestPowerBlade(t, "Off")
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(200*time.Millisecond))
+	start := time.Now()
+	_, err := pt.PowerExecutor(P_ON)
+	elapsed := time.Since(start)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.True(t, elapsed >= 200*time.Millisecond, "expected to wait at least 200ms, waited %s", elapsed)
+	assert.True(t, elapsed < 2*time.Second, "expected to wait less than 2s, waited %s", elapsed)
+}
+
+// TestPowerCycle verify a blade is powered off and back on
+func TestPowerCycle(t *testing.T) {
+	blade, _ := getTestPowerBlade(t, "On")
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithSettleTime(10*time.Millisecond))
+	assert.Equal(t, 10*time.Millisecond, pt.SettleTime)
+	state, err := pt.PowerCycle()
+	assert.NoError(t, err, "PowerCycle threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+}
+
+// TestPowerStateUnknown verify the deprecated alias matches P_UNKNOWN
+func TestPowerStateUnknown(t *testing.T) {
+	assert.Equal(t, P_UNKNOWN, P_UKNOWN)
+	assert.Equal(t, "UNKNOWN", P_UNKNOWN.String())
+}
+
+// TestPowerStateEqual verify matching ignores case and surrounding whitespace
+func TestPowerStateEqual(t *testing.T) {
+	assert.True(t, P_ON.Equal("On"))
+	assert.True(t, P_ON.Equal("ON"))
+	assert.True(t, P_POWERINGOFF.Equal(" poweringoff\n"))
+	assert.False(t, P_ON.Equal("Off"))
+	assert.False(t, P_ON.Equal("O n"))
+	assert.False(t, PowerState(0).Equal("On"))
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { P_POWERINGON.Equal(" PoweringOn ") }))
+}
+
+// TestPowerStateAPIValue verify the exact and lower case forms of power states and controls
+func TestPowerStateAPIValue(t *testing.T) {
+	assert.Equal(t, "On", P_ON.APIValue())
+	assert.Equal(t, "Unknown", P_UNKNOWN.APIValue())
+	assert.Equal(t, "UNKNOWN", P_UNKNOWN.String())
+	assert.Equal(t, "poweringoff", P_POWERINGOFF.Lower())
+	assert.Equal(t, "", PowerState(0).APIValue())
+	assert.Equal(t, "", PowerState(0).Lower())
+	state, err := ParsePowerState(P_UNKNOWN.APIValue())
+	assert.NoError(t, err)
+	assert.Equal(t, P_UNKNOWN, state)
+
+	assert.Equal(t, "PressAndHold", P_PRESSANDHOLD.APIValue())
+	assert.Equal(t, "momentarypress", P_MOMPRESS.Lower())
+	assert.Equal(t, "", PowerControl(0).Lower())
+}
+
+// TestParsePowerState verify appliance power states map back to the enum
+func TestParsePowerState(t *testing.T) {
+	for _, p := range []PowerState{P_ON, P_OFF, P_UNKNOWN, P_POWERINGON, P_POWERINGOFF, P_RESETTING} {
+		state, err := ParsePowerState(p.String())
+		assert.NoError(t, err)
+		assert.Equal(t, p, state)
+	}
+	state, err := ParsePowerState(" off ")
+	assert.NoError(t, err)
+	assert.Equal(t, P_OFF, state)
+
+	for _, s := range []string{"", "Bogus", "On Off"} {
+		state, err = ParsePowerState(s)
+		assert.True(t, errors.Is(err, ErrUnknownPowerState), "expected ErrUnknownPowerState for %q, got %s", s, err)
+		assert.Equal(t, P_UNKNOWN, state)
+	}
+}
+
+// TestPowerExecutorTransition verify the executor waits out a PoweringOn transition
+func TestPowerExecutorTransition(t *testing.T) {
+	blade, b := getTestPowerBlade(t, "Off")
+	b.Transition = "PoweringOn"
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.True(t, P_POWERINGON.IsTransitional())
+	assert.True(t, P_RESETTING.IsTransitional())
+	assert.False(t, P_ON.IsTransitional())
+}
+
+// TestPowerExecutorProgress verify progress is reported on every task check
+func TestPowerExecutorProgress(t *testing.T) {
+	blade, _ := getTestPowerBlade(t, "Off")
+	var (
+		pt       *PowerTask
+		percents []int
+	)
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithProgress(func(percent int, status string) {
+		percents = append(percents, percent)
+	}))
+	_, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, []int{100}, percents)
+}
+
+// TestPowerExecutorMethod verify power changes can be submitted with PATCH
+func TestPowerExecutorMethod(t *testing.T) {
+	blade, b := getTestPowerBlade(t, "Off")
+	b.PowerMethod = rest.PATCH
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	assert.Equal(t, rest.PUT, pt.Method)
+	_, err := pt.PowerExecutor(P_ON)
+	assert.Error(t, err, "PUT should be rejected")
+
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithMethod(rest.PATCH))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+}
+
+// TestPowerExecutorContextDeadline verify a hung task status request is bounded by the context
+func TestPowerExecutorContextDeadline(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "fake, bay 1", "Off")
+	// the task status request never answers until the client gives up
+	f.HandleJSON(rest.PUT, "/rest/server-hardware/1/powerState", `{"uri":"/rest/tasks/hang","taskState":"Running","computedPercentComplete":10}`)
+	f.Handle(rest.GET, "/rest/tasks/hang", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = pt.PowerExecutorContext(ctx, P_ON)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %s", err)
+	assert.True(t, time.Since(start) < 5*time.Second, "task status request was not bounded")
+}
+
+// TestPowerExecutorFakeTransport verify power flows against the in-memory fake appliance
+func TestPowerExecutorFakeTransport(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+
+	// the task never completes within the polls allowed
+	b.TaskPolls = 10
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(3))
+	_, err = pt.PowerExecutor(P_OFF)
+	assert.True(t, errors.Is(err, ErrPowerTimeout), "expected timeout, got %s", err)
+	assert.Equal(t, "On", b.GetState())
+}
+
+// TestPowerExecutorTaskFailed verify a failed power task returns at once instead of timing out
+func TestPowerExecutorTaskFailed(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskState = "Killed"
+	b.TaskError = "iLO did not respond."
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(time.Hour))
+	start := time.Now()
+	_, err = pt.PowerExecutor(P_ON)
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	assert.True(t, time.Since(start) < time.Minute)
+	assert.Contains(t, err.Error(), "iLO did not respond. Retry the power request.")
+	assert.Equal(t, "iLO did not respond. Retry the power request.", pt.ErrorSummary())
+
+	b.TaskState = "Warning"
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithFailStates(T_ERROR, T_WARNING))
+	_, err = pt.PowerExecutor(P_ON)
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected warning to fail, got %s", err)
+	assert.Equal(t, "Off", b.GetState())
+}
+
+// TestPowerExecutorBulk verify blades are powered concurrently up to the cap and failures don't stop the batch
+func TestPowerExecutorBulk(t *testing.T) {
+	f := ovtest.NewFake()
+	f.Latency = 10 * time.Millisecond
+	c := &OVClient{f.NewRestClient()}
+	var (
+		blades   []ServerHardware
+		fakes    []*ovtest.Blade
+		bladeuri = "/rest/server-hardware/%d"
+	)
+	for i := 0; i < 6; i++ {
+		fakes = append(fakes, f.AddBlade(fmt.Sprintf(bladeuri, i), fmt.Sprintf("enc1, bay %d", i), "Off"))
+		blades = append(blades, ServerHardware{Name: fmt.Sprintf("enc1, bay %d", i), URI: utils.NewNstring(fmt.Sprintf(bladeuri, i))})
+	}
+	fakes[3].TaskState = "Error"
+
+	results, err := c.PowerExecutorBulk(blades, P_ON, 2, WithWaitTime(0))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	assert.Contains(t, err.Error(), "enc1, bay 3")
+	assert.Equal(t, 6, len(results))
+	for i, r := range results {
+		assert.Equal(t, blades[i].Name, r.Blade.Name)
+		if i == 3 {
+			assert.Error(t, r.Err)
+			continue
+		}
+		assert.NoError(t, r.Err, "blade %d threw error -> %s", i, r.Err)
+		assert.Equal(t, P_ON, r.RequestedState)
+		assert.Equal(t, P_ON, r.FinalState)
+		assert.True(t, r.Duration > 0)
+		assert.Equal(t, "On", fakes[i].GetState())
+	}
+	assert.True(t, f.MaxInFlight() <= 2, "expected at most 2 concurrent calls, got %d", f.MaxInFlight())
+	assert.True(t, f.MaxInFlight() > 1, "expected blades to be powered concurrently")
+	for _, call := range f.Calls() {
+		assert.False(t, strings.HasPrefix(call.Path, "/rest/tasks/"), "task checks should be batched, got %s", call.Path)
+	}
+
+	failed := results.Failed()
+	assert.Equal(t, 1, len(failed))
+	assert.Equal(t, "enc1, bay 3", failed[0].Blade.Name)
+	assert.Equal(t, P_ON, failed[0].RequestedState)
+	assert.Equal(t, P_OFF, failed[0].FinalState)
+
+	results, err = c.PowerExecutorBulk(blades[:2], P_ON, 0, WithWaitTime(0))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, 0, len(results.Failed()))
+}
+
+// TestPowerExecutorDryRun verify a dry run plans the power request without submitting it
+func TestPowerExecutorDryRun(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithDryRun(), WithMethod(rest.PATCH))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, &PowerPlan{
+		Method:  rest.PATCH,
+		URI:     "/rest/server-hardware/1/powerState",
+		Request: PowerRequest{PowerState: "On", PowerControl: "MomentaryPress"},
+		Current: P_OFF,
+		Desired: P_ON,
+		Change:  true,
+	}, pt.Plan)
+	assert.Equal(t, 0, len(b.PowerRequests()))
+
+	pt = pt.NewPowerTask(blade)
+	plan, err := pt.SubmitPowerStateDryRun(P_OFF)
+	assert.NoError(t, err, "SubmitPowerStateDryRun threw error -> %s", err)
+	assert.False(t, plan.Change)
+	assert.Equal(t, rest.PUT, plan.Method)
+	assert.True(t, pt.TaskIsDone)
+	assert.Equal(t, 0, len(b.PowerRequests()))
+}
+
+// TestPowerRequestJSON verify the exact json sent for each power state and control
+func TestPowerRequestJSON(t *testing.T) {
+	var tests = []struct {
+		state   PowerState
+		control PowerControl
+		request string
+		strict  string
+	}{
+		{P_ON, P_MOMPRESS, `{"powerState":"On","powerControl":"MomentaryPress"}`, `{"powerState":"On","powerControl":"MomentaryPress"}`},
+		{P_ON, P_COLDBOOT, `{"powerState":"On","powerControl":"ColdBoot"}`, `{"powerState":"On","powerControl":"ColdBoot"}`},
+		{P_ON, P_RESET, `{"powerState":"On","powerControl":"Reset"}`, `{"powerState":"On","powerControl":"Reset"}`},
+		{P_ON, P_PRESSANDHOLD, `{"powerState":"On","powerControl":"PressAndHold"}`, `{"powerState":"On","powerControl":"PressAndHold"}`},
+		{P_ON, 0, `{"powerState":"On"}`, `{"powerState":"On","powerControl":""}`},
+		{P_OFF, P_MOMPRESS, `{"powerState":"Off","powerControl":"MomentaryPress"}`, `{"powerState":"Off","powerControl":"MomentaryPress"}`},
+		{P_OFF, P_COLDBOOT, `{"powerState":"Off","powerControl":"ColdBoot"}`, `{"powerState":"Off","powerControl":"ColdBoot"}`},
+		{P_OFF, P_RESET, `{"powerState":"Off","powerControl":"Reset"}`, `{"powerState":"Off","powerControl":"Reset"}`},
+		{P_OFF, P_PRESSANDHOLD, `{"powerState":"Off","powerControl":"PressAndHold"}`, `{"powerState":"Off","powerControl":"PressAndHold"}`},
+		{P_OFF, 0, `{"powerState":"Off"}`, `{"powerState":"Off","powerControl":""}`},
+	}
+	for _, test := range tests {
+		r := PowerRequest{PowerState: test.state.String(), PowerControl: test.control.String()}
+		data, err := json.Marshal(r)
+		assert.NoError(t, err)
+		assert.Equal(t, test.request, string(data))
+		data, err = json.Marshal(PowerRequestStrict(r))
+		assert.NoError(t, err)
+		assert.Equal(t, test.strict, string(data))
+
+		var back PowerRequest
+		assert.NoError(t, json.Unmarshal(data, &back))
+		assert.Equal(t, r, back)
+	}
+}
+
+// TestPowerExecutorStrictRequest verify strict requests always carry powerControl
+func TestPowerExecutorStrictRequest(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithStrictRequest())
+	_, err = pt.PowerExecutorWithControl(P_ON, 0)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	var sent []interface{}
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware/1/powerState" {
+			sent = append(sent, call.Options)
+		}
+	}
+	assert.Equal(t, []interface{}{PowerRequestStrict{PowerState: "On"}}, sent)
+}
+
+// TestPowerStateJSON verify power states marshal to and from the api values
+func TestPowerStateJSON(t *testing.T) {
+	type payload struct {
+		State PowerState `json:"state"`
+	}
+	for _, p := range []PowerState{P_ON, P_OFF, P_UNKNOWN, P_POWERINGON, P_POWERINGOFF, P_RESETTING} {
+		data, err := json.Marshal(payload{State: p})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"state":"`+p.APIValue()+`"}`, string(data))
+		var back payload
+		assert.NoError(t, json.Unmarshal(data, &back))
+		assert.Equal(t, p, back.State)
+	}
+
+	data, err := json.Marshal(payload{State: P_UNKNOWN})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"state":"Unknown"}`, string(data))
+
+	var back payload
+	assert.NoError(t, json.Unmarshal([]byte(`{"state":"poweringon"}`), &back))
+	assert.Equal(t, P_POWERINGON, back.State)
+	assert.NoError(t, json.Unmarshal([]byte(`{"state":null}`), &back))
+	assert.Equal(t, P_POWERINGON, back.State)
+
+	for _, bad := range []string{`{"state":""}`, `{"state":"Bogus"}`} {
+		err := json.Unmarshal([]byte(bad), &back)
+		assert.True(t, errors.Is(err, ErrUnknownPowerState), "expected ErrUnknownPowerState for %s, got %s", bad, err)
+	}
+	assert.Error(t, json.Unmarshal([]byte(`{"state":1}`), &back))
+
+	_, err = json.Marshal(payload{})
+	assert.True(t, errors.Is(err, ErrUnknownPowerState), "expected zero state to fail, got %s", err)
+}
+
+// TestPowerExecutorSoftTimeout verify a progressing power task isn't timed out early
+func TestPowerExecutorSoftTimeout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 5
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(3), WithSoftTimeout(10))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+}
+
+// TestPowerTaskSnapshot verify snapshots can be read while the executor runs
+func TestPowerTaskSnapshot(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 3
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(time.Millisecond))
+	stop := make(chan struct{})
+	seen := make(chan []int)
+	go func() {
+		var percents []int
+		for {
+			select {
+			case <-stop:
+				seen <- percents
+				return
+			default:
+				percents = append(percents, pt.Snapshot().Percent)
+			}
+		}
+	}()
+	_, err = pt.PowerExecutor(P_ON)
+	close(stop)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.True(t, len(<-seen) > 0)
+
+	snap := pt.Snapshot()
+	assert.Equal(t, PowerSnapshot{
+		State:        P_ON,
+		Percent:      100,
+		TaskState:    "Completed",
+		Done:         true,
+		TaskURI:      "/rest/tasks/ovtest-1",
+		BladeName:    "enc1, bay 1",
+		SerialNumber: "OVTESTenc1, bay 1",
+	}, snap)
+}
+
+// TestPowerTaskString verify the log summary of a power task
+func TestPowerTaskString(t *testing.T) {
+	var pt *PowerTask
+	assert.Equal(t, "<nil>", fmt.Sprintf("%v", pt))
+	assert.Equal(t, "PowerState(0)", powerStateName(0))
+	assert.Equal(t, "(*ov.PowerTask)(nil)", fmt.Sprintf("%#v", pt))
+
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(5))
+	assert.Equal(t, "power task enc1, bay 1 (OVTESTenc1, bay 1) state UNKNOWN, task not submitted, timeout 5 checks of 0s", fmt.Sprintf("%+v", pt))
+	_, err = pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, "power task enc1, bay 1 (OVTESTenc1, bay 1) state On, task /rest/tasks/ovtest-1 Completed 100%, timeout 5 checks of 0s", pt.String())
+	assert.Equal(t, `&ov.PowerTask{Blade:"enc1, bay 1", SerialNumber:"OVTESTenc1, bay 1", State:"On", TaskURI:"/rest/tasks/ovtest-1", TaskState:"Completed", Percent:100, TaskIsDone:true, Timeout:5, WaitTime:"0s"}`, fmt.Sprintf("%#v", pt))
+}
+
+// TestWaitForPowerAndHealth verify power on waits for an OK hardware status
+func TestWaitForPowerAndHealth(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	b.Statuses = []string{"Warning", "Warning", "Warning", "OK"}
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.WaitForPowerAndHealth()
+	assert.NoError(t, err, "WaitForPowerAndHealth threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, "OK", pt.Blade.Status)
+
+	b.State, b.Statuses = "Off", []string{"Critical"}
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(4))
+	state, err = pt.WaitForPowerAndHealth()
+	assert.True(t, errors.Is(err, ErrHardwareNotHealthy), "expected unhealthy, got %s", err)
+	assert.Contains(t, err.Error(), "Critical")
+	assert.Equal(t, P_ON, state)
+
+	// a context deadline ends the health wait instead of sleeping out the checks
+	b.State, b.Statuses = "On", []string{"Critical"}
+	pt = pt.NewPowerTask(blade, WithWaitTime(time.Hour), WithTimeout(4))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	state, err = pt.WaitForPowerAndHealthContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline, got %s", err)
+	assert.True(t, time.Since(start) < 10*time.Second, "health wait ignored the context")
+	assert.Equal(t, P_ON, state)
+}
+
+// TestPowerTaskPowerOnOff verify the named wrappers match the executor
+func TestPowerTaskPowerOnOff(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.PowerStatus()
+	assert.NoError(t, err, "PowerStatus threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+
+	state, err = pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+
+	state, err = pt.PowerOff()
+	assert.NoError(t, err, "PowerOff threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, []string{"On", "Off"}, b.PowerRequests())
+
+	state, err = pt.NewPowerTask(ServerHardware{}).PowerStatus()
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+	assert.Equal(t, P_UNKNOWN, state)
+}
+
+// TestPowerExecutorAbsentPowerState verify a blade read without a power state, as in
+// POST, is waited on until it reports one
+func TestPowerExecutorAbsentPowerState(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	absent := 0
+	f.Handle(rest.GET, b.URI, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		if len(b.PowerRequests()) > 0 && absent < 2 {
+			absent++
+			return []byte(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","status":"OK"}`), nil
+		}
+		return []byte(fmt.Sprintf(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","powerState":%q}`, b.GetState())), nil
+	})
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, 2, absent)
+
+	absent = 0
+	state, err = pt.PowerStatus()
+	assert.True(t, errors.Is(err, ErrPowerStateAbsent), "expected absent power state, got %s", err)
+	assert.Equal(t, P_UNKNOWN, state)
+}
+
+// stubHardwareClient - ServerHardwareClient answering from memory, without rest calls
+type stubHardwareClient struct {
+	mu    sync.Mutex
+	state string
+	calls []string
+}
+
+func (s *stubHardwareClient) RestAPICallContext(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+	return s.RestAPICall(method, path, options)
+}
+
+func (s *stubHardwareClient) RestAPICall(method rest.Method, path string, options interface{}) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, method.String()+" "+path)
+	if r, ok := options.(PowerRequest); ok {
+		s.state = r.PowerState
+	}
+	return []byte(`{"uri":"/rest/tasks/stub","taskState":"Completed"}`), nil
+}
+
+func (s *stubHardwareClient) GetServerHardware(uri utils.Nstring) (ServerHardware, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ServerHardware{URI: uri, Name: "stub", PowerState: s.state, Client: s}, nil
+}
+
+func (s *stubHardwareClient) RefreshServerHardware(uri utils.Nstring, opts ...TaskOption) (*Task, error) {
+	return &Task{TaskIsDone: true}, nil
+}
+
+func (s *stubHardwareClient) IsHardwareSchemaV2() bool { return true }
+
+// TestPowerExecutorStubClient verify power logic runs against any ServerHardwareClient
+func TestPowerExecutorStubClient(t *testing.T) {
+	var _ ServerHardwareClient = &OVClient{}
+	stub := &stubHardwareClient{state: "Off"}
+	blade, err := stub.GetServerHardware("/rest/server-hardware/stub")
+	assert.NoError(t, err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.PowerExecutor(P_ON)
+	assert.NoError(t, err, "PowerExecutor threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"PUT /rest/server-hardware/stub/powerState", "GET /rest/tasks/stub"}, stub.calls)
+
+	results, err := (&OVClient{}).PowerExecutorBulk([]ServerHardware{blade}, P_OFF, 1, WithWaitTime(0), WithTaskBatcher(nil))
+	assert.NoError(t, err, "PowerExecutorBulk threw error -> %s", err)
+	assert.Equal(t, P_OFF, results[0].FinalState)
+	assert.Equal(t, stub, results[0].Blade.Client)
+}
+
+// TestPowerExecutorNotRequestable verify reported only states are refused before any request
+func TestPowerExecutorNotRequestable(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	calls := len(f.Calls())
+
+	for _, s := range []PowerState{P_UNKNOWN, P_POWERINGON, P_POWERINGOFF, P_RESETTING, PowerState(0)} {
+		var pt *PowerTask
+		pt = pt.NewPowerTask(blade, WithWaitTime(0))
+		_, err := pt.PowerExecutor(s)
+		assert.True(t, errors.Is(err, ErrPowerStateNotRequestable), "expected ErrPowerStateNotRequestable for %d, got %v", int(s), err)
+		_, err = pt.SubmitPowerStateDryRun(s)
+		assert.True(t, errors.Is(err, ErrPowerStateNotRequestable), "expected ErrPowerStateNotRequestable for %d, got %v", int(s), err)
+	}
+	assert.Equal(t, calls, len(f.Calls()))
+	assert.Equal(t, 0, len(b.PowerRequests()))
+	assert.True(t, P_ON.IsRequestable())
+	assert.True(t, P_OFF.IsRequestable())
+}
+
+// TestWaitForPowerState verify the power state is polled until it matches, without a task
+func TestWaitForPowerState(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	states := []string{"Off", "PoweringOn", "", "On"}
+	f.Handle(rest.GET, "/rest/server-hardware/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		state := states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		return json.Marshal(map[string]string{"uri": "/rest/server-hardware/1", "name": "enc1, bay 1", "powerState": state})
+	})
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.WaitForPowerState(P_ON)
+	assert.NoError(t, err, "WaitForPowerState threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"On"}, states)
+
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(2))
+	state, err = pt.WaitForPowerState(P_OFF)
+	assert.True(t, errors.Is(err, ErrPowerTimeout), "expected timeout, got %s", err)
+	assert.Equal(t, P_ON, state)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pt.WaitForPowerStateContext(ctx, P_OFF)
+	assert.Equal(t, context.Canceled, err)
+}
 
+// TestPowerTaskReboot verify a blade that is on is reset without powering it off,
+// and a blade that is off is refused before any request
+func TestPowerTaskReboot(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	state, err := pt.Reboot()
+	assert.NoError(t, err, "Reboot threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"On"}, b.PowerRequests())
+	assert.Equal(t, []string{"Reset"}, b.PowerControls())
+
+	// the blade reports Resetting after the task completes, it is waited out
+	b.Transition = "Resetting"
+	state, err = pt.NewPowerTask(blade, WithWaitTime(0)).Reboot()
+	assert.NoError(t, err, "Reboot threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, []string{"On", "On"}, b.PowerRequests())
+	b.Transition = ""
+
+	b.State = "Off"
+	state, err = pt.NewPowerTask(blade, WithWaitTime(0)).Reboot()
+	assert.True(t, errors.Is(err, ErrPowerResetOff), "expected reset off, got %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, []string{"On", "On"}, b.PowerRequests())
+}
+
+// TestValidatePowerRequest verify nonsensical state and control combinations are refused
+// locally, before any request
+func TestValidatePowerRequest(t *testing.T) {
+	for _, ok := range []struct {
+		s  PowerState
+		pc PowerControl
+	}{{P_ON, P_MOMPRESS}, {P_ON, P_RESET}, {P_ON, P_COLDBOOT}, {P_ON, 0}, {P_OFF, P_MOMPRESS}, {P_OFF, P_PRESSANDHOLD}, {P_OFF, 0}} {
+		assert.NoError(t, ValidatePowerRequest(ok.s, ok.pc), "%s %s", ok.s, ok.pc)
+	}
+	err := ValidatePowerRequest(P_OFF, P_COLDBOOT)
+	assert.True(t, errors.Is(err, ErrInvalidPowerRequest))
+	assert.Equal(t, "Invalid power request, power control ColdBoot can't be used to power Off", err.Error())
+	assert.True(t, errors.Is(ValidatePowerRequest(P_OFF, P_RESET), ErrInvalidPowerRequest))
+	assert.True(t, errors.Is(ValidatePowerRequest(P_ON, P_PRESSANDHOLD), ErrInvalidPowerRequest))
+	assert.True(t, errors.Is(ValidatePowerRequest(P_ON, PowerControl(9)), ErrInvalidPowerRequest))
+	assert.True(t, errors.Is(ValidatePowerRequest(P_POWERINGON, P_MOMPRESS), ErrPowerStateNotRequestable))
+
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	calls := len(f.Calls())
+	var pt *PowerTask
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerExecutorWithControl(P_OFF, P_COLDBOOT)
+	assert.True(t, errors.Is(err, ErrInvalidPowerRequest), "expected invalid power request, got %s", err)
+	assert.Equal(t, calls, len(f.Calls()))
+	assert.Equal(t, 0, len(b.PowerRequests()))
+}
+
+// TestPowerExecutorBladeBusy verify a blade mid profile apply, refreshing or in maintenance
+// is refused before a power request is sent, unless it is already in the desired state
+func TestPowerExecutorBladeBusy(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	for _, busy := range []func(){
+		func() { b.HardwareState = "ApplyingProfile" },
+		func() { b.HardwareState, b.RefreshState = "ProfileApplied", "Refreshing" },
+		func() { b.RefreshState, b.MaintenanceMode = "NotRefreshing", true },
+	} {
+		busy()
+		_, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+		assert.True(t, errors.Is(err, ErrBladeBusy), "expected blade busy, got %s", err)
+	}
+	assert.Equal(t, "Blade doesn't accept power changes, enc1, bay 1 is in maintenance mode", err.Error())
+	state, err := pt.NewPowerTask(blade, WithWaitTime(0)).PowerOff()
+	assert.NoError(t, err, "PowerOff threw error -> %s", err)
+	assert.Equal(t, P_OFF, state)
+	assert.Equal(t, 0, len(b.PowerRequests()))
+
+	b.MaintenanceMode = false
+	state, err = pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+}
+
+// TestSetDefaultPowerTimeout verify new power tasks start from the package defaults,
+// options still override them
+func TestSetDefaultPowerTimeout(t *testing.T) {
+	defer SetDefaultPowerTimeout(0)
+	defer SetDefaultPowerWaitTime(-1)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(ServerHardware{})
+	assert.Equal(t, 36, pt.Timeout)
+	assert.Equal(t, 10*time.Second, pt.WaitTime)
+
+	SetDefaultPowerTimeout(90)
+	SetDefaultPowerWaitTime(2 * time.Second)
+	pt = pt.NewPowerTask(ServerHardware{})
+	assert.Equal(t, 90, pt.Timeout)
+	assert.Equal(t, 2*time.Second, pt.WaitTime)
+	pt = pt.NewPowerTask(ServerHardware{}, WithTimeout(5), WithWaitTime(0))
+	assert.Equal(t, 5, pt.Timeout)
+	assert.Equal(t, time.Duration(0), pt.WaitTime)
+
+	SetDefaultPowerTimeout(0)
+	SetDefaultPowerWaitTime(-1)
+	pt = pt.NewPowerTask(ServerHardware{})
+	assert.Equal(t, DefaultPowerTimeout, pt.Timeout)
+	assert.Equal(t, DefaultPowerWaitTime, pt.WaitTime)
+}
+
+// TestPowerExecutorVerifyState verify a task that completes without the blade reaching the
+// requested state, as after a double momentary press, fails with ErrPowerStateMismatch
+func TestPowerExecutorVerifyState(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskState = "Warning"
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	state, err := pt.NewPowerTask(blade, WithWaitTime(0)).PowerOn()
+	assert.True(t, errors.Is(err, ErrPowerStateMismatch), "expected mismatch, got %s", err)
+	assert.Equal(t, "Power On failed for enc1, bay 1, current power state is Off: Power state not reached", err.Error())
+	assert.Equal(t, P_OFF, state)
+}
+
+// TestEnsurePowerState verify only blades not in the desired state are submitted, and
+// every blade is waited on until it reports it
+func TestEnsurePowerState(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var (
+		blades []ServerHardware
+		fakes  []*ovtest.Blade
+	)
+	for i, state := range []string{"Off", "On", "Off"} {
+		uri := fmt.Sprintf("/rest/server-hardware/%d", i)
+		b := f.AddBlade(uri, fmt.Sprintf("enc1, bay %d", i), state)
+		b.ApplyOnRead = true
+		fakes = append(fakes, b)
+		blades = append(blades, ServerHardware{Name: b.Name, URI: utils.NewNstring(uri)})
+	}
+	failed, err := c.EnsurePowerState(blades, P_ON, time.Minute, WithWaitTime(0))
+	assert.NoError(t, err, "EnsurePowerState threw error -> %s", err)
+	assert.Equal(t, 0, len(failed))
+	assert.Equal(t, []string{"On"}, fakes[0].PowerRequests())
+	assert.Equal(t, 0, len(fakes[1].PowerRequests()))
+	assert.Equal(t, "On", fakes[2].GetState())
+
+	// a blade that never gets there times out, the others still succeed
+	fakes[1].TaskState = "Warning"
+	fakes[1].ApplyOnRead = false
+	failed, err = c.EnsurePowerState(blades, P_OFF, 50*time.Millisecond, WithWaitTime(time.Millisecond))
+	assert.True(t, errors.Is(err, ErrPowerTimeout), "expected timeout, got %s", err)
+	assert.Equal(t, 1, len(failed))
+	assert.True(t, errors.Is(failed["/rest/server-hardware/1"], ErrPowerTimeout))
+	assert.Equal(t, "Off", fakes[0].GetState())
+	assert.Equal(t, "Off", fakes[2].GetState())
+
+	_, err = c.EnsurePowerState(blades, P_POWERINGON, 0)
+	assert.True(t, errors.Is(err, ErrPowerStateNotRequestable))
+}
+
+// TestPowerTaskProgressSteps verify each progress update of the power task is reported once, in order
+func TestPowerTaskProgressSteps(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 2
+	b.Steps = []string{"Set power lock.", "Press power button.", "Release power lock."}
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var steps []string
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithProgressSteps(func(step ProgressUpdate) {
+		steps = append(steps, step.StatusUpdate)
+	}))
+	state, err := pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, b.Steps, steps)
+}
+
+// TestPowerTaskModelTimeout verify the timeout is picked by the blade model, other models
+// keep the fixed Timeout
+func TestPowerTaskModelTimeout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.Model, b.TaskPolls = "ProLiant BL460c Gen9", 3
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	timeouts := WithModelTimeouts(map[string]int{"proliant bl460c gen9": 10, "ProLiant DL380 Gen10": 1})
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(1), timeouts)
+	state, err := pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, 10, pt.Timeout)
+
+	b.Model = "ProLiant DL580 Gen10"
+	_, err = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(1), timeouts).PowerOff()
+	assert.True(t, errors.Is(err, ErrPowerTimeout), "expected timeout, got %s", err)
+}
+
+// a blade without a Client, decoded from json for example, fails instead of panicking
+func TestPowerTaskNoClient(t *testing.T) {
+	var b ServerHardware
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","powerState":"Off"}`), &b))
+	var pt *PowerTask
+	pt = pt.NewPowerTask(b, WithWaitTime(0))
+	err := pt.GetCurrentPowerState()
+	assert.True(t, errors.Is(err, ErrNoBladeClient), "expected no client, got %s", err)
+	assert.Equal(t, "ServerHardware has no Client configured, for /rest/server-hardware/1", err.Error())
+	state, err := pt.PowerExecutor(P_ON)
+	assert.True(t, errors.Is(err, ErrNoBladeClient), "expected no client, got %s", err)
+	assert.Equal(t, P_UNKNOWN, state)
+	pt.SubmitPowerState(P_ON)
+	assert.True(t, pt.TaskIsDone)
+
+	var c *OVClient
+	b.Client = c
+	_, err = b.GetPowerState()
+	assert.True(t, errors.Is(err, ErrNoBladeClient), "expected no client, got %s", err)
+	assert.True(t, errors.Is(b.PowerOn(), ErrNoBladeClient))
+}
+
+// wait times below the minimum are raised to it, set with WithWaitTime or later
+func TestMinPowerWaitTime(t *testing.T) {
+	SetMinPowerWaitTime(-1)
+	defer SetMinPowerWaitTime(0)
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	assert.Equal(t, DefaultMinPowerWaitTime, pt.WaitTime)
+	pt = pt.NewPowerTask(blade, WithWaitTime(5*time.Second))
+	assert.Equal(t, 5*time.Second, pt.WaitTime, "above the minimum")
+
+	SetMinPowerWaitTime(50 * time.Millisecond)
+	pt = pt.NewPowerTask(blade)
+	pt.WaitTime = time.Millisecond
+	state, err := pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+	assert.Equal(t, 50*time.Millisecond, pt.WaitTime, "clamped when the power change starts")
+	pt.MaxWaitTime, pt.Jitter = time.Millisecond, 1
+	assert.Equal(t, 50*time.Millisecond, pt.GetWaitTime(0), "floor after the ceiling and jitter")
+
+	pt = &PowerTask{Blade: blade}
+	pt.Timeout = 1
+	_, err = pt.WaitForPowerState(P_ON)
+	assert.NoError(t, err, "WaitForPowerState threw error -> %s", err)
+	assert.Equal(t, 50*time.Millisecond, pt.MinWaitTime, "floor set by the state wait")
+
+	SetMinPowerWaitTime(0)
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	assert.Equal(t, time.Duration(0), pt.WaitTime)
+}
+
+// the strict constructor fails on a blade without uri or Client
+func TestNewPowerTaskStrict(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt, err = pt.NewPowerTaskStrict(blade, WithTimeout(7), WithWaitTime(0))
+	assert.NoError(t, err, "NewPowerTaskStrict threw error -> %s", err)
+	assert.Equal(t, 7, pt.Timeout)
+	state, err := pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+
+	pt, err = pt.NewPowerTaskStrict(ServerHardware{Client: c})
+	assert.True(t, errors.Is(err, ErrNoBladeHardware), "expected no hardware, got %s", err)
+	assert.Nil(t, pt)
+	blade.Client = nil
+	pt, err = pt.NewPowerTaskStrict(blade)
+	assert.True(t, errors.Is(err, ErrNoBladeClient), "expected no client, got %s", err)
+	assert.Nil(t, pt)
+}
+
+// a power task queued on a busy appliance gets its own pending timeout
+func TestPowerTaskPendingTimeout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.PendingPolls, b.TaskPolls = 3, 1
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(3), WithPendingTimeout(5))
+	state, err := pt.PowerOn()
+	assert.NoError(t, err, "PowerOn threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+
+	// still queued once the pending checks are used up, the task is cancelled
+	b.PendingPolls, b.Cancellable = 10, true
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithTimeout(30), WithPendingTimeout(3))
+	_, err = pt.PowerOff()
+	assert.True(t, errors.Is(err, ErrTaskPendingTimeout), "expected pending timeout, got %s", err)
+	cancelled := false
+	for wait := 0; wait < 100 && !cancelled; wait++ {
+		for _, call := range f.Calls() {
+			cancelled = cancelled || (call.Method == rest.PUT && strings.HasPrefix(call.Path, "/rest/tasks/"))
+		}
+		time.Sleep(10 * time.Millisecond)
 	}
+	assert.True(t, cancelled, "the queued power task is cancelled")
+	assert.Equal(t, "On", b.GetState())
 }
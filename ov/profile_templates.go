@@ -23,7 +23,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/liboneview"
 	"github.com/HewlettPackard/oneview-golang/rest"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // introduced in v200 for oneview, allows for an easier method
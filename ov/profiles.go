@@ -24,7 +24,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // FirmwareOption structure for firware settings
@@ -260,6 +259,80 @@ func (c *OVClient) SubmitNewProfile(p ServerProfile) (t *Task, err error) {
 	return t, err
 }
 
+// SubmitUpdateProfile - submit changes to an existing profile, p.URI is replaced
+func (c *OVClient) SubmitUpdateProfile(p ServerProfile) (t *Task, err error) {
+	log.Infof("Initializing update of server profile for %s.", p.Name)
+	t = t.NewProfileTask(c)
+	t.ResetTask()
+	if p.URI.IsNil() {
+		t.TaskIsDone = true
+		return t, errors.New("Unable to update profile, no uri found")
+	}
+	log.Debugf("REST : %s \n %+v\n", p.URI, p)
+	data, err := c.RestAPICall(rest.PUT, p.URI.String(), p)
+	if err != nil {
+		t.TaskIsDone = true
+		log.Errorf("Error submitting update profile request: %s", err)
+		return t, err
+	}
+
+	log.Debugf("Response UpdateProfile %s", data)
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		t.TaskIsDone = true
+		log.Errorf("Error with task un-marshal: %s", err)
+		return t, err
+	}
+	return t, nil
+}
+
+// ApplyProfileAndPower - power the blade off, assign profile p to it, wait for
+// the profile task, then power on and verify. When the profile fails the blade
+// is put back in the power state it started in. The profile task is checked
+// every WaitTime of the power task, up to the 144 checks of a profile task.
+func (c *OVClient) ApplyProfileAndPower(p ServerProfile, blade ServerHardware, opts ...PowerTaskOption) error {
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, opts...)
+	prior, err := pt.PowerStatus()
+	if err != nil {
+		return fmt.Errorf("Error getting power state of %s before applying profile: %w", blade.Name, err)
+	}
+	if prior != P_OFF {
+		if _, err := pt.PowerExecutor(P_OFF); err != nil {
+			return fmt.Errorf("Error powering off %s before applying profile: %w", blade.Name, err)
+		}
+	}
+
+	p.ServerHardwareURI = blade.URI
+	var t *Task
+	if p.URI.IsNil() {
+		t, err = c.SubmitNewProfile(p)
+	} else {
+		t, err = c.SubmitUpdateProfile(p)
+	}
+	if err == nil {
+		_, err = c.WaitForTask(t.URI.String(), t.Timeout, pt.WaitTime)
+	}
+	if err != nil {
+		err = fmt.Errorf("Error applying profile %s to %s: %w", p.Name, blade.Name, err)
+		if prior == P_ON {
+			log.Warnf("Restoring power on for %s after failed profile %s.", blade.Name, p.Name)
+			if _, perr := pt.PowerExecutor(P_ON); perr != nil {
+				return errors.Join(err, fmt.Errorf("Error restoring power on for %s: %w", blade.Name, perr))
+			}
+		}
+		return err
+	}
+
+	state, err := pt.PowerExecutor(P_ON)
+	if err != nil {
+		return fmt.Errorf("Error powering on %s after applying profile: %w", blade.Name, err)
+	}
+	if state != P_ON {
+		return fmt.Errorf("Power on failed for %s after applying profile, current power state is %s: %w", blade.Name, state, ErrPowerStateMismatch)
+	}
+	return nil
+}
+
 // create profile from template
 func (c *OVClient) CreateProfileFromTemplate(name string, template ServerProfile, blade ServerHardware) error {
 	log.Debugf("TEMPLATE : %+v\n", template)
@@ -305,7 +378,7 @@ func (c *OVClient) SubmitDeleteProfile(p ServerProfile) (t *Task, err error) {
 	log.Debugf("REST : %s \n %+v\n", uri, p)
 	log.Debugf("task -> %+v", t)
 	if uri == "" {
-		log.Warn("Unable to post delete, no uri found.")
+		log.Warnf("Unable to post delete, no uri found.")
 		t.TaskIsDone = true
 		return t, err
 	}
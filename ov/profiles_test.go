@@ -1,11 +1,14 @@
 package ov
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
 
-	"github.com/docker/machine/libmachine/log"
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,7 +33,7 @@ func TestCreateProfileFromTemplate(t *testing.T) {
 		testBladeSerial = d.Tc.GetTestData(d.Env, "FreeBladeSerialNumber").(string)
 		testTemplateName = d.Tc.GetTestData(d.Env, "TemplateProfile").(string)
 		if os.Getenv("ONEVIEW_TEST_PROVISION") != "true" {
-			log.Info("env ONEVIEW_TEST_PROVISION != true for TestCreateProfileFromTemplate")
+			log.Infof("env ONEVIEW_TEST_PROVISION != true for TestCreateProfileFromTemplate")
 			log.Infof("Skipping, create profile for : %s, %s, %s", testHostName, testBladeSerial, testTemplateName)
 			return
 		}
@@ -70,8 +73,8 @@ func TestCreateProfileFromTemplate(t *testing.T) {
 		log.Debugf("testBlade -> %+v", testBlade)
 		pt = pt.NewPowerTask(testBlade)
 		pt.Timeout = 46 // timeout is 20 sec
-		log.Info("------- Setting Power to On")
-		err = pt.PowerExecutor(P_ON)
+		log.Infof("------- Setting Power to On")
+		_, err = pt.PowerExecutor(P_ON)
 		assert.NoError(t, err, "PowerExecutor threw no errors -> %s", err)
 	}
 
@@ -246,3 +249,43 @@ func TestDeleteProfile(t *testing.T) {
 	}
 
 }
+
+// test apply profile and power, the blade is off while the profile applies and
+// goes back to its prior power state when the profile fails
+func TestApplyProfileAndPower(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var applied []string
+	f.Handle(rest.POST, "/rest/server-profiles", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		p := options.(ServerProfile)
+		applied = append(applied, fmt.Sprintf("%s %s %s", p.Name, p.ServerHardwareURI, b.GetState()))
+		return []byte(`{"uri":"/rest/tasks/profile","taskState":"Running"}`), nil
+	})
+	f.HandleJSON(rest.GET, "/rest/tasks/profile", `{"uri":"/rest/tasks/profile","name":"Create","taskState":"Completed"}`)
+
+	err = c.ApplyProfileAndPower(ServerProfile{Name: "web01"}, blade, WithWaitTime(0))
+	assert.NoError(t, err, "ApplyProfileAndPower threw error -> %s", err)
+	assert.Equal(t, []string{"web01 /rest/server-hardware/1 Off"}, applied)
+	assert.Equal(t, []string{"Off", "On"}, b.PowerRequests())
+	assert.Equal(t, "On", b.GetState())
+
+	// profile fails, power is restored
+	f.HandleJSON(rest.GET, "/rest/tasks/profile", `{"uri":"/rest/tasks/profile","name":"Create","taskState":"Error",
+		"taskErrors":[{"message":"Connections not valid."}]}`)
+	err = c.ApplyProfileAndPower(ServerProfile{Name: "web02"}, blade, WithWaitTime(0))
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	assert.Contains(t, err.Error(), "Connections not valid.")
+	assert.Equal(t, []string{"Off", "On", "Off", "On"}, b.PowerRequests())
+	assert.Equal(t, "On", b.GetState())
+
+	// blade was off, it stays off
+	b.State = "Off"
+	err = c.ApplyProfileAndPower(ServerProfile{Name: "web03"}, blade, WithWaitTime(0))
+	assert.Error(t, err)
+	assert.Equal(t, 4, len(b.PowerRequests()))
+	assert.Equal(t, "Off", b.GetState())
+}
@@ -0,0 +1,165 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// captureExtras - 1 when unknown fields are kept in RawExtras, see CaptureRawExtras
+var captureExtras int32
+
+// CaptureRawExtras - keep the json fields ServerHardware and Task don't know in
+// their RawExtras when they are decoded from now on, so fields of newer api
+// versions can be read without a library upgrade, off by default
+func CaptureRawExtras(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&captureExtras, v)
+}
+
+// knownFields - json keys of the fields of a struct type, lower case as keys are
+// matched case insensitively, by type
+var knownFields sync.Map
+
+// jsonKeys - json keys the fields of struct type t decode from, embedded
+// structs included
+func jsonKeys(t reflect.Type) map[string]bool {
+	if keys, ok := knownFields.Load(t); ok {
+		return keys.(map[string]bool)
+	}
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k := range jsonKeys(ft) {
+					keys[k] = true
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported
+		}
+		if name == "" {
+			name = f.Name
+		}
+		keys[strings.ToLower(name)] = true
+	}
+	knownFields.Store(t, keys)
+	return keys
+}
+
+// rawExtras - fields of the json object b no field of struct type t decodes,
+// nil when capturing is off or there are none
+func rawExtras(b []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	if atomic.LoadInt32(&captureExtras) == 0 {
+		return nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	keys := jsonKeys(t)
+	for k := range fields {
+		if keys[strings.ToLower(k)] {
+			delete(fields, k)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// UnmarshalJSON - decode the server hardware, fields it doesn't know are kept in
+// RawExtras when CaptureRawExtras is on
+func (h *ServerHardware) UnmarshalJSON(b []byte) error {
+	type serverHardware ServerHardware
+	if err := json.Unmarshal(b, (*serverHardware)(h)); err != nil {
+		return err
+	}
+	extras, err := rawExtras(b, reflect.TypeOf(*h))
+	if err != nil {
+		return err
+	}
+	h.RawExtras = extras
+	return nil
+}
+
+// UnmarshalJSON - decode the task, fields it doesn't know are kept in RawExtras
+// when CaptureRawExtras is on
+func (t *Task) UnmarshalJSON(b []byte) error {
+	type task Task
+	if err := json.Unmarshal(b, (*task)(t)); err != nil {
+		return err
+	}
+	extras, err := rawExtras(b, reflect.TypeOf(*t))
+	if err != nil {
+		return err
+	}
+	t.RawExtras = extras
+	return nil
+}
+
+// UnmarshalJSON - decode the power task as before Task had its own UnmarshalJSON,
+// the task fields at the top and the Blade and State of the power task
+func (pt *PowerTask) UnmarshalJSON(b []byte) error {
+	var power struct {
+		Blade *ServerHardware
+		State *PowerState
+	}
+	if err := json.Unmarshal(b, &power); err != nil {
+		return err
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if err := pt.Task.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	for k := range pt.Task.RawExtras {
+		if strings.EqualFold(k, "Blade") || strings.EqualFold(k, "State") {
+			delete(pt.Task.RawExtras, k)
+		}
+	}
+	if len(pt.Task.RawExtras) == 0 {
+		pt.Task.RawExtras = nil
+	}
+	if power.Blade != nil {
+		pt.Blade = *power.Blade
+	}
+	if power.State != nil {
+		pt.State = *power.State
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package ov
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// raw extras test, unknown fields are only kept when capturing is on
+func TestRawExtras(t *testing.T) {
+	data := []byte(`{"type":"server-hardware-9","uri":"/rest/server-hardware/1","PowerState":"On",
+		"mpState":"OK","powerLock":false,"quantumState":{"entangled":true},"futureFlag":1}`)
+	var b ServerHardware
+	assert.NoError(t, json.Unmarshal(data, &b))
+	assert.Nil(t, b.RawExtras, "capturing is off by default")
+	assert.Equal(t, "On", b.PowerState)
+
+	CaptureRawExtras(true)
+	defer CaptureRawExtras(false)
+	b = ServerHardware{}
+	assert.NoError(t, json.Unmarshal(data, &b))
+	assert.Equal(t, "On", b.PowerState, "keys match case insensitively")
+	assert.Equal(t, "OK", b.MpState, "embedded fields are known")
+	assert.Len(t, b.RawExtras, 2)
+	assert.JSONEq(t, `{"entangled":true}`, string(b.RawExtras["quantumState"]))
+	assert.Equal(t, "1", string(b.RawExtras["futureFlag"]))
+
+	var list ServerHardwareList
+	assert.NoError(t, json.Unmarshal([]byte(`{"members":[{"name":"bay 1","newField":"x"}]}`), &list))
+	assert.Equal(t, `"x"`, string(list.Members[0].RawExtras["newField"]))
+
+	var task Task
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri":"/rest/tasks/1","taskState":"Completed","stepDurations":[3,4]}`), &task))
+	assert.Equal(t, "Completed", task.TaskState)
+	assert.Equal(t, "[3,4]", string(task.RawExtras["stepDurations"]))
+	task.RawExtras = nil
+	assert.NoError(t, json.Unmarshal([]byte(`{"uri":"/rest/tasks/1"}`), &task))
+	assert.Nil(t, task.RawExtras)
+
+	// a power task keeps its own fields, they aren't extras of its task
+	var pt PowerTask
+	assert.NoError(t, json.Unmarshal([]byte(`{"Blade":{"name":"bay 2"},"State":"Off","taskState":"Running","newField":2}`), &pt))
+	assert.Equal(t, "bay 2", pt.Blade.Name)
+	assert.Equal(t, P_OFF, pt.State)
+	assert.Equal(t, "Running", pt.TaskState)
+	assert.Equal(t, map[string]json.RawMessage{"newField": json.RawMessage("2")}, pt.Task.RawExtras)
+}
+
+// raw extras from the appliance, the fields the fake sends that the struct has not
+func TestRawExtrasGetServerHardware(t *testing.T) {
+	CaptureRawExtras(true)
+	defer CaptureRawExtras(false)
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1", `{"uri":"/rest/server-hardware/1","name":"bay 1","rackPosition":"U12"}`)
+	hw, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	assert.Equal(t, `"U12"`, string(hw.RawExtras["rackPosition"]))
+	assert.NotNil(t, hw.Client)
+}
@@ -20,12 +20,15 @@ package ov
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // HardwareState
@@ -70,6 +73,21 @@ func (h HardwareState) Equal(s string) bool {
 	return (strings.ToUpper(s) == strings.ToUpper(h.String()))
 }
 
+var (
+	// ErrNoCompatibleHardware - no blades are compatible with the server profile
+	ErrNoCompatibleHardware = errors.New("Error! No available blades that are compatible with the server profile!")
+	// ErrNoAvailableHardware - all compatible blades already have a profile applied
+	ErrNoAvailableHardware = errors.New("No more blades are available for provisioning!")
+	// ErrServerHardwareNotFound - no server hardware matched the lookup
+	ErrServerHardwareNotFound = errors.New("Server hardware not found")
+	// ErrServerHardwareAmbiguous - more than one server hardware matched the lookup
+	ErrServerHardwareAmbiguous = errors.New("More than one server hardware matched")
+	// ErrBladeBusy - the blade doesn't accept power changes now, see AcceptsPowerChange
+	ErrBladeBusy = errors.New("Blade doesn't accept power changes")
+	// ErrBladeNotReady - the blade wasn't ready for power changes in time, see ReadyForPower
+	ErrBladeNotReady = errors.New("Blade is not ready for power changes")
+)
+
 // ServerHardware get server hardware from ov
 type ServerHardware struct {
 	ServerHardwarev200
@@ -81,12 +99,14 @@ type ServerHardware struct {
 	FormFactor            string        `json:"formFactor,omitempty"`            // "formFactor": "HalfHeight",
 	LicensingIntent       string        `json:"licensingIntent,omitempty"`       // "licensingIntent": "OneView",
 	LocationURI           utils.Nstring `json:"locationUri,omitempty"`           // "locationUri": "/rest/enclosures/092SN51207RR",
+	MaintenanceMode       bool          `json:"maintenanceMode,omitempty"`       // "maintenanceMode": false,
 	MemoryMb              int           `json:"memoryMb,omitempty"`              // "memoryMb": 262144,
 	Model                 string        `json:"model,omitempty"`                 // "model": "ProLiant BL460c Gen9",
 	Modified              string        `json:"modified,omitempty"`              // "modified": "2015-09-01T22:42:50.086Z",
 	MpFirwareVersion      string        `json:"mpFirmwareVersion,omitempty"`     // "mpFirmwareVersion": "2.03 Nov 07 2014",
 	MpModel               string        `json:"mpModel,omitempty"`               // "mpModel": "iLO4",
 	Name                  string        `json:"name,omitempty"`                  // "name": "se05, bay 16",
+	OneTimeBoot           string        `json:"oneTimeBoot,omitempty"`           // "oneTimeBoot": "Normal",
 	PartNumber            string        `json:"partNumber,omitempty"`            // "partNumber": "727021-B21",
 	Position              int           `json:"position,omitempty"`              // "position": 16,
 	PowerLock             bool          `json:"powerLock,omitempty"`             // "powerLock": false,
@@ -106,6 +126,7 @@ type ServerHardware struct {
 	StateReason           string        `json:"stateReason,omitempty"`           // "stateReason": "NotApplicable",
 	Status                string        `json:"status,omitempty"`                // "status": "Warning",
 	Type                  string        `json:"type,omitempty"`                  // "type": "server-hardware-3",
+	UIDState              string        `json:"uidState,omitempty"`              // "uidState": "Off",
 	URI                   utils.Nstring `json:"uri,omitempty"`                   // "uri": "/rest/server-hardware/30373237-3132-4D32-3235-303930524D57",
 	UUID                  utils.Nstring `json:"uuid,omitempty"`                  // "uuid": "30373237-3132-4D32-3235-303930524D57",
 	VirtualSerialNumber   utils.Nstring `json:"VirtualSerialNumber,omitempty"`   // "virtualSerialNumber": "",
@@ -114,7 +135,71 @@ type ServerHardware struct {
 	MpDnsName   string `json:"mpDnsName,omitempty"`   // "mpDnsName": "ILO2M25090RMW",
 	MpIpAddress string `json:"mpIpAddress,omitempty"` // make this private to force calls to GetIloIPAddress() "mpIpAddress": "172.28.3.136",
 	// extra client struct
-	Client *OVClient
+	Client ServerHardwareClient
+	// fields the server hardware was sent that aren't known, see CaptureRawExtras
+	RawExtras map[string]json.RawMessage `json:"-"`
+}
+
+// ServerHardwareClient - the calls ServerHardware and PowerTask make to the
+// appliance, satisfied by *OVClient, stub it to test power logic
+type ServerHardwareClient interface {
+	TaskClient
+	RestAPICall(method rest.Method, path string, options interface{}) ([]byte, error)
+	GetServerHardware(uri utils.Nstring) (ServerHardware, error)
+	RefreshServerHardware(uri utils.Nstring, opts ...TaskOption) (*Task, error)
+	IsHardwareSchemaV2() bool
+}
+
+// hasClient - false for a nil ServerHardwareClient, a nil *OVClient included
+func hasClient(c ServerHardwareClient) bool {
+	if c == nil {
+		return false
+	}
+	if client, ok := c.(*OVClient); ok && client == nil {
+		return false
+	}
+	return true
+}
+
+// GetEnclosureURI - uri of the enclosure the blade is in, false for rack
+// servers that aren't located in an enclosure
+func (h ServerHardware) GetEnclosureURI() (utils.Nstring, bool) {
+	if h.LocationURI.IsNil() || !strings.HasPrefix(string(h.LocationURI), "/rest/enclosures/") {
+		return "", false
+	}
+	return h.LocationURI, true
+}
+
+// GetBay - bay number of the blade in its enclosure, 0 when not known
+func (h ServerHardware) GetBay() int {
+	return h.Position
+}
+
+// powerBusyStates - server hardware states in which the appliance refuses power changes
+var powerBusyStates = []string{"Adding", "Removing", "ApplyingProfile", "RemovingProfile", "UpdatingFirmware"}
+
+// AcceptsPowerChange - nil when the blade accepts power changes, an error wrapping
+// ErrBladeBusy when it is in maintenance mode, refreshing or in the middle of a
+// profile operation, checked before power requests are sent
+func (h ServerHardware) AcceptsPowerChange() error {
+	if h.MaintenanceMode {
+		return fmt.Errorf("%w, %s is in maintenance mode", ErrBladeBusy, h.Name)
+	}
+	if strings.EqualFold(h.RefreshState, "Refreshing") {
+		return fmt.Errorf("%w, %s is refreshing", ErrBladeBusy, h.Name)
+	}
+	for _, state := range powerBusyStates {
+		if strings.EqualFold(h.State, state) {
+			return fmt.Errorf("%w, %s is in state %s", ErrBladeBusy, h.Name, h.State)
+		}
+	}
+	return nil
+}
+
+// GetMpFirmwareVersion - firmware version of the management processor (iLO),
+// reported as mpFirmwareVersion, the field is MpFirwareVersion
+func (h ServerHardware) GetMpFirmwareVersion() string {
+	return h.MpFirwareVersion
 }
 
 // GetIloIPAddress - Use MpIpAddress for v1 and
@@ -123,7 +208,7 @@ type ServerHardware struct {
 func (h ServerHardware) GetIloIPAddress() string {
 	if h.Client.IsHardwareSchemaV2() {
 		if h.MpHostInfo != nil {
-			log.Debug("working on getting IloIPAddress from MpHostInfo")
+			log.Debugf("working on getting IloIPAddress from MpHostInfo")
 			for _, MpIpObj := range h.MpHostInfo.MpIPAddress {
 				if len(MpIpObj.Address) > 0 &&
 					(MpDHCP.Equal(MpIpObj.Type) ||
@@ -155,28 +240,84 @@ type ServerHardwareList struct {
 	Members     []ServerHardware `json:"members,omitempty"`     //"members":[]
 }
 
-// server hardware power off
+// server hardware power off, a graceful shutdown: the OS is asked to shut down
+// with a MomentaryPress and may ignore it, see PowerOffForce
 func (s ServerHardware) PowerOff() error {
 	var pt *PowerTask
 	pt = pt.NewPowerTask(s)
-	return pt.PowerExecutor(P_OFF)
+	_, err := pt.PowerExecutor(P_OFF)
+	return err
+}
+
+// server hardware forced power off, an immediate PressAndHold shutdown that
+// doesn't wait on the OS, returns the appliance error when the request is rejected
+func (s ServerHardware) PowerOffForce(opts ...PowerTaskOption) error {
+	var pt *PowerTask
+	pt = pt.NewPowerTask(s, opts...)
+	state, err := pt.PowerExecutorWithControl(P_OFF, P_PRESSANDHOLD)
+	if err != nil {
+		return err
+	}
+	if state != P_OFF {
+		return fmt.Errorf("Forced power off failed for %s, current power state is %s: %w", s.Name, state, ErrPowerStateMismatch)
+	}
+	return nil
 }
 
 // server hardware power on
 func (s ServerHardware) PowerOn() error {
 	var pt *PowerTask
 	pt = pt.NewPowerTask(s)
-	return pt.PowerExecutor(P_ON)
+	_, err := pt.PowerExecutor(P_ON)
+	return err
 }
 
-// get the power state
+// get the power state, without the need for a PowerTask
+// returns P_UNKNOWN with an error when the power state isn't recognized
 func (s ServerHardware) GetPowerState() (PowerState, error) {
-	var pt *PowerTask
-	pt = pt.NewPowerTask(s)
-	if err := pt.GetCurrentPowerState(); err != nil {
-		return P_UKNOWN, err
+	if s.URI.IsNil() {
+		return P_UNKNOWN, ErrNoBladeHardware
+	}
+	if !hasClient(s.Client) {
+		return P_UNKNOWN, fmt.Errorf("%w, for %s", ErrNoBladeClient, s.URI)
 	}
-	return pt.State, nil
+	b, err := s.Client.GetServerHardware(s.URI)
+	if err != nil {
+		return P_UNKNOWN, err
+	}
+	state, err := ParsePowerState(b.PowerState)
+	if err != nil {
+		return P_UNKNOWN, fmt.Errorf("%w detected %s, for %s.", ErrUnknownPowerState, b.PowerState, b.Name)
+	}
+	return state, nil
+}
+
+// ServerHardwareRefresh - request body for a server hardware refresh
+type ServerHardwareRefresh struct {
+	RefreshState string `json:"refreshState"` // "RefreshPending"
+}
+
+// RefreshServerHardware - ask the appliance to re-read the state of the server hardware at uri
+// and wait for the refresh task, by default with the power timeout and wait time, see SetDefaultPowerTimeout
+func (c *OVClient) RefreshServerHardware(uri utils.Nstring, opts ...TaskOption) (*Task, error) {
+	if uri.IsNil() {
+		return nil, ErrNoBladeHardware
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+
+	data, err := c.RestAPICall(rest.PUT, uri.String()+"/refreshState", ServerHardwareRefresh{RefreshState: "RefreshPending"})
+	if err != nil {
+		return nil, fmt.Errorf("Error with server hardware refresh request: %w", err)
+	}
+	log.Debugf("RefreshServerHardware %s", data)
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, err
+	}
+	timeout, wait := powerTaskDefaults()
+	return c.WaitForTask(task.URI.String(), timeout, wait, opts...)
 }
 
 // get a server hardware with uri
@@ -201,6 +342,131 @@ func (c *OVClient) GetServerHardware(uri utils.Nstring) (ServerHardware, error)
 	return hardware, nil
 }
 
+// DefaultEventualInterval - wait between reads of GetServerHardwareEventual
+const DefaultEventualInterval = 2 * time.Second
+
+// GetServerHardwareEventual - get the server hardware at uri, a 404 is retried every
+// interval for up to window, as hardware just added answers 404 for a while, a
+// server hardware still missing after window is ErrServerHardwareNotFound, other
+// errors are returned at once, interval <= 0 uses DefaultEventualInterval
+func (c *OVClient) GetServerHardwareEventual(uri utils.Nstring, window, interval time.Duration) (ServerHardware, error) {
+	if interval <= 0 {
+		interval = DefaultEventualInterval
+	}
+	deadline := time.Now().Add(window)
+	for {
+		hardware, err := c.GetServerHardware(uri)
+		if err == nil || !isNotFound(err) {
+			return hardware, err
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return hardware, fmt.Errorf("%w, %s after %s: %v", ErrServerHardwareNotFound, uri, window, err)
+		}
+		log.Debugf("Server hardware %s not available yet, retrying in %s", uri, interval)
+		time.Sleep(interval)
+	}
+}
+
+// ReadyForPower - wait up to timeout, reading the blade every interval, until the
+// blade at uri is present, not refreshing, reports a known power state and accepts
+// power changes, nil once it does, ErrBladeNotReady with the last reason when
+// timeout passes, errors other than a 404 are returned at once, interval <= 0
+// uses DefaultEventualInterval
+func (c *OVClient) ReadyForPower(uri utils.Nstring, timeout, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultEventualInterval
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		var reason error
+		hardware, err := c.GetServerHardware(uri)
+		switch {
+		case err != nil && !isNotFound(err):
+			return err
+		case err != nil:
+			reason = fmt.Errorf("%s is not present", uri)
+		default:
+			reason = hardware.readyForPower()
+		}
+		if reason == nil {
+			return nil
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return fmt.Errorf("%w after %s, %v", ErrBladeNotReady, timeout, reason)
+		}
+		log.Debugf("Server hardware %s not ready for power yet, %s, retrying in %s", uri, reason, interval)
+		time.Sleep(interval)
+	}
+}
+
+// readyForPower - nil when the blade is done refreshing, reports a known power
+// state and accepts power changes, the reason it isn't ready otherwise, an
+// appliance leaving refreshState out is taken as not refreshing
+func (h ServerHardware) readyForPower() error {
+	if h.RefreshState != "" && !strings.EqualFold(h.RefreshState, "NotRefreshing") {
+		return fmt.Errorf("%s refresh state is %s", h.Name, h.RefreshState)
+	}
+	if state, err := ParsePowerState(h.PowerState); err != nil || state == P_UNKNOWN {
+		return fmt.Errorf("%s power state is %q", h.Name, h.PowerState)
+	}
+	return h.AcceptsPowerChange()
+}
+
+// isNotFound - true when err is a 404 Not Found answer
+func isNotFound(err error) bool {
+	var serr *rest.StatusError
+	return errors.As(err, &serr) && serr.StatusCode == http.StatusNotFound
+}
+
+// GetServerHardwareBySerialNumber - get the single server hardware with serial number sn
+func (c *OVClient) GetServerHardwareBySerialNumber(sn string) (ServerHardware, error) {
+	return c.getServerHardwareByFilter(fmt.Sprintf("serialNumber=%s", filterValue(sn)), "serial number "+sn)
+}
+
+// GetServerHardwareByUUID - get the single server hardware with uuid, the
+// appliance stores uuids upper case so lower case ones from iLO also match
+func (c *OVClient) GetServerHardwareByUUID(uuid string) (ServerHardware, error) {
+	uuid = strings.ToUpper(strings.TrimSpace(uuid))
+	return c.getServerHardwareByFilter(fmt.Sprintf("uuid=%s", filterValue(uuid)), "uuid "+uuid)
+}
+
+// GetServerHardwareByName - get the single server hardware named name
+func (c *OVClient) GetServerHardwareByName(name string) (ServerHardware, error) {
+	return c.getServerHardwareByFilter(fmt.Sprintf("name=%s", filterValue(name)), "name "+name)
+}
+
+// GetServerHardwareByEnclosure - get all the blades in the enclosure at
+// enclosureURI ordered by bay
+func (c *OVClient) GetServerHardwareByEnclosure(enclosureURI string) ([]ServerHardware, error) {
+	if strings.TrimSpace(enclosureURI) == "" {
+		return nil, errors.New("Error enclosure uri is required to get its server hardware")
+	}
+	hwlist, err := c.GetServerHardwareList([]string{fmt.Sprintf("locationUri=%s", filterValue(enclosureURI))}, "position:asc")
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(hwlist.Members, func(i, j int) bool {
+		return hwlist.Members[i].GetBay() < hwlist.Members[j].GetBay()
+	})
+	return hwlist.Members, nil
+}
+
+// getServerHardwareByFilter - get the single server hardware matching filter
+func (c *OVClient) getServerHardwareByFilter(filter string, what string) (ServerHardware, error) {
+	var hardware ServerHardware
+	hwlist, err := c.GetServerHardwareList([]string{filter}, "name:asc")
+	if err != nil {
+		return hardware, err
+	}
+	switch len(hwlist.Members) {
+	case 0:
+		return hardware, fmt.Errorf("%w with %s", ErrServerHardwareNotFound, what)
+	case 1:
+		return hwlist.Members[0], nil
+	}
+	return hardware, fmt.Errorf("%w with %s, found %d", ErrServerHardwareAmbiguous, what, len(hwlist.Members))
+}
+
 // get a server hardware with filters
 func (c *OVClient) GetServerHardwareList(filters []string, sort string) (ServerHardwareList, error) {
 	var (
@@ -220,26 +486,56 @@ func (c *OVClient) GetServerHardwareList(filters []string, sort string) (ServerH
 	// refresh login
 	c.RefreshLogin()
 	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
-	// Setup query
-	if len(q) > 0 {
+	// don't leave the paging query behind for the next call on this client
+	defer c.SetQueryString(make(map[string]interface{}))
+
+	// follow nextPageUri until the whole collection is assembled
+	for page := 0; uri != ""; page++ {
+		var pagelist ServerHardwareList
 		c.SetQueryString(q)
+		data, err := c.RestAPICall(rest.GET, uri, nil)
+		if err != nil {
+			return serverlist, err
+		}
+
+		log.Debugf("GetServerHardwareList %s", data)
+		if err := json.Unmarshal([]byte(data), &pagelist); err != nil {
+			return serverlist, err
+		}
+
+		if page == 0 {
+			serverlist = pagelist
+			serverlist.Members = nil
+		}
+		for _, s := range pagelist.Members {
+			s.Client = c
+			serverlist.Members = append(serverlist.Members, s)
+		}
+
+		uri, q, err = splitPageURI(pagelist.NextPageURI)
+		if err != nil {
+			return serverlist, err
+		}
 	}
-	data, err := c.RestAPICall(rest.GET, uri, nil)
+	serverlist.Count = len(serverlist.Members)
+	serverlist.NextPageURI = utils.NewNstring("")
+	return serverlist, nil
+}
 
-	// parse the url and setup any query strings
-	var Url *url.URL
-	Url, err = url.Parse(utils.Sanatize(c.Endpoint))
+// splitPageURI - split a next page uri into the path and the query it carries
+func splitPageURI(next utils.Nstring) (string, map[string]interface{}, error) {
+	q := make(map[string]interface{})
+	if next.IsNil() {
+		return "", q, nil
+	}
+	u, err := url.Parse(next.String())
 	if err != nil {
-		return serverlist, err
+		return "", q, err
 	}
-	Url.Path += uri
-	c.GetQueryString(Url)
-
-	log.Debugf("GetServerHardwareList %s", data)
-	if err := json.Unmarshal([]byte(data), &serverlist); err != nil {
-		return serverlist, err
+	for k, v := range u.Query() {
+		q[k] = v
 	}
-	return serverlist, nil
+	return u.Path, q, nil
 }
 
 // get available server
@@ -254,7 +550,7 @@ func (c *OVClient) GetAvailableHardware(hardwaretype_uri utils.Nstring, servergr
 		return hw, err
 	}
 	if !(len(hwlist.Members) > 0) {
-		return hw, errors.New("Error! No available blades that are compatible with the server profile!")
+		return hw, ErrNoCompatibleHardware
 	}
 
 	// pick an available blade
@@ -265,7 +561,7 @@ func (c *OVClient) GetAvailableHardware(hardwaretype_uri utils.Nstring, servergr
 		}
 	}
 	if hw.Name == "" {
-		return hw, errors.New("No more blades are available for provisioning!")
+		return hw, ErrNoAvailableHardware
 	}
 	return hw, nil
 }
@@ -0,0 +1,92 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// unknownAssetTag - asset tag the appliance reports when none was set
+const unknownAssetTag = "[Unknown]"
+
+// Label - a label assigned to a resource
+type Label struct {
+	Name string        `json:"name,omitempty"` // "name": "rack-12",
+	URI  utils.Nstring `json:"uri,omitempty"`  // "uri": "/rest/labels/1"
+}
+
+// ResourceLabels - the labels assigned to a resource
+type ResourceLabels struct {
+	Category    string        `json:"category,omitempty"`    // "category": "resource-labels",
+	ETAG        string        `json:"eTag,omitempty"`        // "eTag": "1441147370086",
+	Labels      []Label       `json:"labels"`                // "labels": [],
+	ResourceURI utils.Nstring `json:"resourceUri,omitempty"` // "resourceUri": "/rest/server-hardware/30373237-3132-4D32-3235-303930524D57",
+	Type        string        `json:"type,omitempty"`        // "type": "ResourceLabels",
+	URI         utils.Nstring `json:"uri,omitempty"`         // "uri": "/rest/labels/resources/rest/server-hardware/30373237-3132-4D32-3235-303930524D57"
+}
+
+// Names - names of the labels
+func (r ResourceLabels) Names() []string {
+	names := make([]string, 0, len(r.Labels))
+	for _, l := range r.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// GetAssetTag - asset tag of the server hardware, empty when the appliance
+// reports it as unknown
+func (h ServerHardware) GetAssetTag() string {
+	tag := strings.TrimSpace(h.AssetTag)
+	if tag == unknownAssetTag {
+		return ""
+	}
+	return tag
+}
+
+// GetServerHardwareLabels - get the labels assigned to the server hardware at uri,
+// none when the appliance has no labels for it
+func (c *OVClient) GetServerHardwareLabels(uri utils.Nstring) (ResourceLabels, error) {
+	labels := ResourceLabels{ResourceURI: uri, Labels: []Label{}}
+	if uri.IsNil() {
+		return labels, ErrNoBladeHardware
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+
+	data, err := c.RestAPICall(rest.GET, "/rest/labels/resources"+uri.String(), nil)
+	if err != nil {
+		if isNotFound(err) {
+			return labels, nil
+		}
+		return labels, err
+	}
+	log.Debugf("GetServerHardwareLabels %s", data)
+	if err := json.Unmarshal([]byte(data), &labels); err != nil {
+		return labels, err
+	}
+	if labels.Labels == nil {
+		labels.Labels = []Label{}
+	}
+	return labels, nil
+}
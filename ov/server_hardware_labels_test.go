@@ -0,0 +1,44 @@
+package ov
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// labels test, assigned labels are read, a resource without labels has none
+func TestGetServerHardwareLabels(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/labels/resources/rest/server-hardware/1",
+		`{"type":"ResourceLabels","resourceUri":"/rest/server-hardware/1","category":"resource-labels",
+		"labels":[{"name":"rack-12","uri":"/rest/labels/1"},{"name":"cmdb:web","uri":"/rest/labels/2"}]}`)
+	f.HandleStatus(rest.GET, "/rest/labels/resources/rest/server-hardware/3", http.StatusForbidden, "not authorized")
+
+	labels, err := c.GetServerHardwareLabels("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardwareLabels threw error -> %s", err)
+	assert.Equal(t, "/rest/server-hardware/1", labels.ResourceURI.String())
+	assert.Equal(t, []string{"rack-12", "cmdb:web"}, labels.Names())
+	assert.Equal(t, "/rest/labels/2", labels.Labels[1].URI.String())
+
+	labels, err = c.GetServerHardwareLabels("/rest/server-hardware/2")
+	assert.NoError(t, err, "no labels isn't an error -> %s", err)
+	assert.Empty(t, labels.Names())
+	assert.NotNil(t, labels.Labels)
+
+	_, err = c.GetServerHardwareLabels("/rest/server-hardware/3")
+	assert.Error(t, err)
+	_, err = c.GetServerHardwareLabels("")
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+}
+
+// asset tag test, the unknown tag the appliance reports reads as empty
+func TestGetAssetTag(t *testing.T) {
+	assert.Equal(t, "CMDB-0042", ServerHardware{AssetTag: " CMDB-0042 "}.GetAssetTag())
+	assert.Equal(t, "", ServerHardware{AssetTag: "[Unknown]"}.GetAssetTag())
+	assert.Equal(t, "", ServerHardware{}.GetAssetTag())
+}
@@ -0,0 +1,81 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ErrNoPortMap - the server hardware reports no port map, rack servers and
+// appliances older than api version 200 don't
+var ErrNoPortMap = errors.New("Server hardware has no port map")
+
+// ServerPort - a physical port of a server hardware adapter, with the slot it is in
+type ServerPort struct {
+	Location         string        // "Flb", "Lom" or "Mezz"
+	SlotNumber       int           // slot of the adapter within Location
+	DeviceName       string        // name or model of the adapter
+	PortNumber       int           // port number on the adapter
+	Type             string        // "Ethernet", "FibreChannel", ...
+	MAC              string        // mac address of the port, for ethernet
+	WWN              string        // world wide name of the port, for fibre channel
+	InterconnectURI  utils.Nstring // interconnect hosting the network connections of the port
+	InterconnectPort int           // downlink port on the interconnect, 0 when not connected
+	VirtualPorts     []VirtualPortv200
+}
+
+// Ports - the physical ports of every device slot, in slot and port order as reported
+func (p PortMapv200) Ports() []ServerPort {
+	var ports []ServerPort
+	for _, slot := range p.DeviceSlots {
+		for _, port := range slot.PhysicalPorts {
+			ports = append(ports, ServerPort{
+				Location:         slot.Location,
+				SlotNumber:       slot.SlotNumber,
+				DeviceName:       slot.DeviceName,
+				PortNumber:       port.PortNumber,
+				Type:             port.Type,
+				MAC:              port.MAC,
+				WWN:              port.WWN,
+				InterconnectURI:  port.InterconnectURI,
+				InterconnectPort: port.InterconnectPort,
+				VirtualPorts:     port.VirtualPorts,
+			})
+		}
+	}
+	return ports
+}
+
+// GetServerHardwarePortMap - get the adapter slots and ports of the server hardware
+// at uri, with their macs and wwns, ErrNoPortMap when it reports none
+func (c *OVClient) GetServerHardwarePortMap(uri utils.Nstring) (PortMapv200, error) {
+	if uri.IsNil() {
+		return PortMapv200{}, ErrNoBladeHardware
+	}
+	hardware, err := c.GetServerHardware(uri)
+	if err != nil {
+		return PortMapv200{}, err
+	}
+	if hardware.PortMap == nil {
+		return PortMapv200{}, fmt.Errorf("%w, %s", ErrNoPortMap, hardware.Name)
+	}
+	return *hardware.PortMap, nil
+}
@@ -0,0 +1,45 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// port map test, slots and ports are read with their addresses, rack servers have none
+func TestGetServerHardwarePortMap(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1", `{"uri":"/rest/server-hardware/1","name":"enc1, bay 1",
+		"portMap":{"deviceSlots":[
+		{"deviceName":"HP FlexFabric 20Gb 2-port 650FLB Adapter","location":"Flb","slotNumber":1,"physicalPorts":[
+			{"portNumber":1,"type":"Ethernet","mac":"9C:B6:54:8A:74:40","interconnectUri":"/rest/interconnects/1","interconnectPort":1,
+			"virtualPorts":[{"portFunction":"a","portNumber":1,"mac":"9C:B6:54:8A:74:41"}]},
+			{"portNumber":2,"type":"Ethernet","mac":"9C:B6:54:8A:74:48","interconnectUri":"/rest/interconnects/2","interconnectPort":1}]},
+		{"deviceName":"HP QMH2572 8Gb FC HBA","location":"Mezz","slotNumber":1,"physicalPorts":[
+			{"portNumber":1,"type":"FibreChannel","wwn":"50:01:43:80:02:5D:1C:10"}]}]}}`)
+	f.HandleJSON(rest.GET, "/rest/server-hardware/2", `{"uri":"/rest/server-hardware/2","name":"rack server"}`)
+
+	pm, err := c.GetServerHardwarePortMap("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardwarePortMap threw error -> %s", err)
+	assert.Len(t, pm.DeviceSlots, 2)
+	ports := pm.Ports()
+	assert.Len(t, ports, 3)
+	assert.Equal(t, "Flb", ports[0].Location)
+	assert.Equal(t, "9C:B6:54:8A:74:40", ports[0].MAC)
+	assert.Equal(t, "/rest/interconnects/1", ports[0].InterconnectURI.String())
+	assert.Equal(t, "a", ports[0].VirtualPorts[0].PortFunction)
+	assert.Equal(t, 2, ports[1].PortNumber)
+	assert.Equal(t, "FibreChannel", ports[2].Type)
+	assert.Equal(t, "50:01:43:80:02:5D:1C:10", ports[2].WWN)
+	assert.Equal(t, "Mezz", ports[2].Location)
+
+	_, err = c.GetServerHardwarePortMap("/rest/server-hardware/2")
+	assert.True(t, errors.Is(err, ErrNoPortMap), "expected no port map, got %s", err)
+	_, err = c.GetServerHardwarePortMap("")
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+	assert.Empty(t, PortMapv200{}.Ports())
+}
@@ -0,0 +1,168 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ErrPowerCapNotSupported - the server hardware model can't be power capped
+var ErrPowerCapNotSupported = errors.New("Power capping is not supported by the server hardware")
+
+// EnvironmentalConfiguration - power management settings of a server hardware
+type EnvironmentalConfiguration struct {
+	CalibratedMaxPower      int              `json:"calibratedMaxPower,omitempty"`      // "calibratedMaxPower": 423,
+	CapHistorySupported     bool             `json:"capHistorySupported,omitempty"`     // "capHistorySupported": true,
+	IdleMaxPower            int              `json:"idleMaxPower,omitempty"`            // "idleMaxPower": 96,
+	PowerCap                int              `json:"powerCap,omitempty"`                // "powerCap": 350,
+	PowerManagement         *PowerManagement `json:"powerManagement,omitempty"`         // "powerManagement": {},
+	PowerCapSupported       bool             `json:"powerCapSupported,omitempty"`       // "powerCapSupported": true,
+	PowerHistorySupported   bool             `json:"powerHistorySupported,omitempty"`   // "powerHistorySupported": true,
+	ThermalHistorySupported bool             `json:"thermalHistorySupported,omitempty"` // "thermalHistorySupported": true,
+	URI                     utils.Nstring    `json:"uri,omitempty"`                     // "uri": "/rest/server-hardware/30373237-3132-4D32-3235-303930524D57/environmentalConfiguration"
+}
+
+// PowerManagement - power management policy of a server hardware, beyond the power cap
+type PowerManagement struct {
+	StaticLimit         int  `json:"staticLimit,omitempty"`         // "staticLimit": 400, watts the server never goes above
+	DynamicCap          bool `json:"dynamicCap"`                    // "dynamicCap": true, the cap moves with the enclosure power budget
+	ExpectedPowerOutput int  `json:"expectedPowerOutput,omitempty"` // "expectedPowerOutput": 280, watts planned for the server
+}
+
+// PowerManagementRequest - body to set the power management policy of a server hardware
+type PowerManagementRequest struct {
+	PowerManagement PowerManagement `json:"powerManagement"`
+}
+
+// PowerCapRequest - body to set the power cap of a server hardware, calibratedMaxPower
+// is left alone, the appliance only lets it be set for unmanaged hardware
+type PowerCapRequest struct {
+	PowerCap int `json:"powerCap"`
+}
+
+// GetEnvironmentalConfiguration - get the power management settings of the server hardware at uri
+func (c *OVClient) GetEnvironmentalConfiguration(uri utils.Nstring) (EnvironmentalConfiguration, error) {
+	var config EnvironmentalConfiguration
+	if uri.IsNil() {
+		return config, ErrNoBladeHardware
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+
+	data, err := c.RestAPICall(rest.GET, uri.String()+"/environmentalConfiguration", nil)
+	if err != nil {
+		return config, err
+	}
+	log.Debugf("GetEnvironmentalConfiguration %s", data)
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// GetPowerCap - get the power cap in watts of the server hardware at uri, 0 when not capped
+func (c *OVClient) GetPowerCap(uri utils.Nstring) (int, error) {
+	config, err := c.GetEnvironmentalConfiguration(uri)
+	if err != nil {
+		return 0, err
+	}
+	if !config.PowerCapSupported {
+		return 0, fmt.Errorf("%w, %s", ErrPowerCapNotSupported, uri)
+	}
+	return config.PowerCap, nil
+}
+
+// SetPowerCap - limit the server hardware at uri to watts and wait for the
+// appliance to apply it
+func (c *OVClient) SetPowerCap(uri utils.Nstring, watts int, opts ...TaskOption) error {
+	if watts <= 0 {
+		return fmt.Errorf("Error power cap has to be more than 0 watts, got %d", watts)
+	}
+	config, err := c.GetEnvironmentalConfiguration(uri)
+	if err != nil {
+		return err
+	}
+	if !config.PowerCapSupported {
+		return fmt.Errorf("%w, %s", ErrPowerCapNotSupported, uri)
+	}
+	if config.CalibratedMaxPower > 0 && watts > config.CalibratedMaxPower {
+		return fmt.Errorf("Error power cap of %d watts is above the calibrated max power of %d watts", watts, config.CalibratedMaxPower)
+	}
+
+	return c.putEnvironmentalConfiguration(uri, PowerCapRequest{PowerCap: watts}, "power cap", opts...)
+}
+
+// GetPowerManagement - get the power management policy of the server hardware at uri
+func (c *OVClient) GetPowerManagement(uri utils.Nstring) (PowerManagement, error) {
+	config, err := c.GetEnvironmentalConfiguration(uri)
+	if err != nil {
+		return PowerManagement{}, err
+	}
+	if !config.PowerCapSupported || config.PowerManagement == nil {
+		return PowerManagement{}, fmt.Errorf("%w, %s", ErrPowerCapNotSupported, uri)
+	}
+	return *config.PowerManagement, nil
+}
+
+// SetPowerManagement - set the power management policy of the server hardware at uri
+// and wait for the appliance to apply it
+func (c *OVClient) SetPowerManagement(uri utils.Nstring, pm PowerManagement, opts ...TaskOption) error {
+	if pm.StaticLimit < 0 || pm.ExpectedPowerOutput < 0 {
+		return fmt.Errorf("Error power management limits can't be negative, got %+v", pm)
+	}
+	config, err := c.GetEnvironmentalConfiguration(uri)
+	if err != nil {
+		return err
+	}
+	if !config.PowerCapSupported {
+		return fmt.Errorf("%w, %s", ErrPowerCapNotSupported, uri)
+	}
+	if config.CalibratedMaxPower > 0 && pm.StaticLimit > config.CalibratedMaxPower {
+		return fmt.Errorf("Error static limit of %d watts is above the calibrated max power of %d watts", pm.StaticLimit, config.CalibratedMaxPower)
+	}
+	return c.putEnvironmentalConfiguration(uri, PowerManagementRequest{PowerManagement: pm}, "power management", opts...)
+}
+
+// putEnvironmentalConfiguration - put body to the power management settings of the
+// server hardware at uri and wait on the task, what names the change in errors
+func (c *OVClient) putEnvironmentalConfiguration(uri utils.Nstring, body interface{}, what string, opts ...TaskOption) error {
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	data, err := c.RestAPICall(rest.PUT, uri.String()+"/environmentalConfiguration", body)
+	if err != nil {
+		return fmt.Errorf("Error with %s request: %w", what, err)
+	}
+	log.Debugf("Set %s %s", what, data)
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return err
+	}
+	// some api versions answer with the updated settings instead of a task
+	if !strings.HasPrefix(task.Type, "Task") {
+		return nil
+	}
+	timeout, wait := powerTaskDefaults()
+	_, err = c.WaitForTask(task.URI.String(), timeout, wait, opts...)
+	return err
+}
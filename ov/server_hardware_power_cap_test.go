@@ -0,0 +1,87 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// power cap test, the cap is read, set with a task and refused on unsupported models
+func TestPowerCap(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var requested PowerCapRequest
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1/environmentalConfiguration",
+		`{"calibratedMaxPower":423,"capHistorySupported":true,"idleMaxPower":96,"powerCap":350,"powerCapSupported":true}`)
+	f.Handle(rest.PUT, "/rest/server-hardware/1/environmentalConfiguration", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		requested = options.(PowerCapRequest)
+		return []byte(`{"type":"TaskResourceV2","uri":"/rest/tasks/cap","taskState":"Running"}`), nil
+	})
+	f.HandleJSON(rest.GET, "/rest/tasks/cap", `{"type":"TaskResourceV2","uri":"/rest/tasks/cap","taskState":"Completed"}`)
+	f.HandleJSON(rest.GET, "/rest/server-hardware/2/environmentalConfiguration", `{"calibratedMaxPower":0,"capHistorySupported":true,"powerCapSupported":false}`)
+
+	watts, err := c.GetPowerCap("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetPowerCap threw error -> %s", err)
+	assert.Equal(t, 350, watts)
+
+	err = c.SetPowerCap("/rest/server-hardware/1", 300, TaskWaitTime(0))
+	assert.NoError(t, err, "SetPowerCap threw error -> %s", err)
+	assert.Equal(t, PowerCapRequest{PowerCap: 300}, requested)
+	assert.Error(t, c.SetPowerCap("/rest/server-hardware/1", 500), "cap above the calibrated max power")
+
+	_, err = c.GetPowerCap("/rest/server-hardware/2")
+	assert.True(t, errors.Is(err, ErrPowerCapNotSupported), "expected not supported, got %s", err)
+	err = c.SetPowerCap("/rest/server-hardware/2", 300)
+	assert.True(t, errors.Is(err, ErrPowerCapNotSupported), "expected not supported, got %s", err)
+	assert.Error(t, c.SetPowerCap("/rest/server-hardware/1", 0))
+	_, err = c.GetPowerCap("")
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+
+	// without options the task is checked with the power defaults
+	f.HandleJSON(rest.GET, "/rest/server-hardware/3/environmentalConfiguration", `{"calibratedMaxPower":423,"powerCapSupported":true}`)
+	f.HandleJSON(rest.PUT, "/rest/server-hardware/3/environmentalConfiguration", `{"type":"TaskResourceV2","uri":"/rest/tasks/stuck","taskState":"Running"}`)
+	f.HandleJSON(rest.GET, "/rest/tasks/stuck", `{"type":"TaskResourceV2","uri":"/rest/tasks/stuck","taskState":"Running"}`)
+	SetDefaultPowerTimeout(2)
+	SetDefaultPowerWaitTime(0)
+	defer SetDefaultPowerTimeout(0)
+	defer SetDefaultPowerWaitTime(-1)
+	err = c.SetPowerCap("/rest/server-hardware/3", 300)
+	assert.True(t, errors.Is(err, ErrTaskTimeout), "expected timeout, got %s", err)
+	assert.Contains(t, err.Error(), "after 2 checks")
+}
+
+// power management test, the full policy round-trips and waits on the task
+func TestPowerManagement(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var requested PowerManagementRequest
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1/environmentalConfiguration",
+		`{"calibratedMaxPower":423,"powerCap":350,"powerCapSupported":true,
+		"powerManagement":{"staticLimit":400,"dynamicCap":true,"expectedPowerOutput":280}}`)
+	f.Handle(rest.PUT, "/rest/server-hardware/1/environmentalConfiguration", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		requested = options.(PowerManagementRequest)
+		return []byte(`{"type":"TaskResourceV2","uri":"/rest/tasks/pm","taskState":"Running"}`), nil
+	})
+	f.HandleJSON(rest.GET, "/rest/tasks/pm", `{"type":"TaskResourceV2","uri":"/rest/tasks/pm","taskState":"Completed"}`)
+	f.HandleJSON(rest.GET, "/rest/server-hardware/2/environmentalConfiguration", `{"powerCapSupported":false}`)
+
+	pm, err := c.GetPowerManagement("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetPowerManagement threw error -> %s", err)
+	assert.Equal(t, PowerManagement{StaticLimit: 400, DynamicCap: true, ExpectedPowerOutput: 280}, pm)
+
+	pm.StaticLimit, pm.DynamicCap = 380, false
+	err = c.SetPowerManagement("/rest/server-hardware/1", pm, TaskWaitTime(0))
+	assert.NoError(t, err, "SetPowerManagement threw error -> %s", err)
+	assert.Equal(t, PowerManagementRequest{PowerManagement: pm}, requested)
+	assert.Error(t, c.SetPowerManagement("/rest/server-hardware/1", PowerManagement{StaticLimit: 500}), "limit above the calibrated max power")
+	assert.Error(t, c.SetPowerManagement("/rest/server-hardware/1", PowerManagement{ExpectedPowerOutput: -1}))
+
+	_, err = c.GetPowerManagement("/rest/server-hardware/2")
+	assert.True(t, errors.Is(err, ErrPowerCapNotSupported), "expected not supported, got %s", err)
+	err = c.SetPowerManagement("/rest/server-hardware/2", pm)
+	assert.True(t, errors.Is(err, ErrPowerCapNotSupported), "expected not supported, got %s", err)
+}
@@ -1,11 +1,20 @@
 package ov
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,3 +93,341 @@ func TestGetIloIPAddress(t *testing.T) {
 	}
 
 }
+
+// TestGetPowerState verify power state strings are mapped from the blade
+func TestGetPowerState(t *testing.T) {
+	blade, _ := getTestPowerBlade(t, "On")
+	state, err := blade.GetPowerState()
+	assert.NoError(t, err, "GetPowerState threw error -> %s", err)
+	assert.Equal(t, P_ON, state)
+
+	blade, _ = getTestPowerBlade(t, "Bogus")
+	state, err = blade.GetPowerState()
+	assert.True(t, errors.Is(err, ErrUnknownPowerState), "expected ErrUnknownPowerState, got %s", err)
+	assert.Equal(t, P_UNKNOWN, state)
+
+	state, err = ServerHardware{}.GetPowerState()
+	assert.True(t, errors.Is(err, ErrNoBladeHardware), "expected ErrNoBladeHardware, got %s", err)
+	assert.Equal(t, P_UNKNOWN, state)
+}
+
+// get server hardware list test, follows nextPageUri across pages
+func TestGetServerHardwareListPaging(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.Handle(rest.GET, "/rest/server-hardware", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+		start := u.Query().Get("start")
+		next := "null"
+		if start == "" {
+			next = `"/rest/server-hardware?filter=name+matches+%27fake%25%27&sort=name%3Aasc&start=2&count=2"`
+			start = "0"
+		}
+		return []byte(fmt.Sprintf(`{"total":3,"count":2,"start":%s,"nextPageUri":%s,"members":[{"name":"fake %s a"},{"name":"fake %s b"}]}`,
+			start, next, start, start)), nil
+	})
+
+	list, err := c.GetServerHardwareList([]string{"name matches 'fake%'"}, "name:asc")
+	assert.NoError(t, err, "GetServerHardwareList threw error -> %s", err)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, 4, list.Count)
+	assert.Equal(t, 4, len(list.Members))
+	assert.Equal(t, "fake 0 a", list.Members[0].Name)
+	assert.Equal(t, "fake 2 b", list.Members[3].Name)
+	assert.True(t, list.NextPageURI.IsNil())
+	for _, s := range list.Members {
+		assert.Equal(t, c, s.Client)
+	}
+	var queries []url.Values
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware" {
+			queries = append(queries, call.Query)
+		}
+	}
+	assert.Equal(t, 2, len(queries))
+	assert.Equal(t, url.Values{"filter": {"name matches 'fake%'"}, "sort": {"name:asc"}}, queries[0])
+	assert.Equal(t, "2", queries[1].Get("start"))
+	assert.Equal(t, 0, len(c.Option.Query))
+}
+
+// get server hardware list test, errors from the appliance are returned
+func TestGetServerHardwareListError(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleStatus(rest.GET, "/rest/server-hardware", http.StatusInternalServerError, `{"errorCode":"INTERNAL_ERROR"}`)
+
+	_, err := c.GetServerHardwareList(nil, "")
+	assert.Error(t, err)
+}
+
+// get server hardware by serial number and name test
+func TestGetServerHardwareBySerialNumber(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var filters []string
+	f.Handle(rest.GET, "/rest/server-hardware", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+		filter := u.Query().Get("filter")
+		filters = append(filters, filter)
+		switch filter {
+		case "serialNumber='SN001'":
+			return []byte(`{"total":1,"members":[{"name":"enc1, bay 1","serialNumber":"SN001"}]}`), nil
+		case "uuid='30373237-3132-4D32-3235-303930524D57'":
+			return []byte(`{"total":1,"members":[{"name":"enc1, bay 3","uuid":"30373237-3132-4D32-3235-303930524D57"}]}`), nil
+		case "name='enc1, bay 2'":
+			return []byte(`{"total":2,"members":[{"name":"enc1, bay 2"},{"name":"enc1, bay 2"}]}`), nil
+		}
+		return []byte(`{"total":0,"members":[]}`), nil
+	})
+
+	hw, err := c.GetServerHardwareBySerialNumber("SN001")
+	assert.NoError(t, err, "GetServerHardwareBySerialNumber threw error -> %s", err)
+	assert.Equal(t, "enc1, bay 1", hw.Name)
+	assert.Equal(t, c, hw.Client)
+
+	_, err = c.GetServerHardwareBySerialNumber("SN404")
+	assert.True(t, errors.Is(err, ErrServerHardwareNotFound), "expected not found, got %s", err)
+
+	_, err = c.GetServerHardwareByName("enc1, bay 2")
+	assert.True(t, errors.Is(err, ErrServerHardwareAmbiguous), "expected ambiguous, got %s", err)
+
+	hw, err = c.GetServerHardwareByUUID(" 30373237-3132-4d32-3235-303930524d57")
+	assert.NoError(t, err, "GetServerHardwareByUUID threw error -> %s", err)
+	assert.Equal(t, "enc1, bay 3", hw.Name)
+	assert.Equal(t, []string{"serialNumber='SN001'", "serialNumber='SN404'", "name='enc1, bay 2'",
+		"uuid='30373237-3132-4D32-3235-303930524D57'"}, filters)
+}
+
+// get the blades of an enclosure test, ordered by bay with their enclosure
+func TestGetServerHardwareByEnclosure(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	e := f.AddEnclosure("/rest/enclosures/enc1", "enc1", 3)
+	for _, bay := range []int{3, 1, 2} {
+		e.AddBlade(f, fmt.Sprintf("/rest/server-hardware/%d", bay), bay, "Off")
+	}
+	f.AddBlade("/rest/server-hardware/rack1", "rack1", "Off")
+
+	blades, err := c.GetServerHardwareByEnclosure("/rest/enclosures/enc1")
+	assert.NoError(t, err, "GetServerHardwareByEnclosure threw error -> %s", err)
+	calls := f.Calls()
+	query := calls[len(calls)-1].Query
+	assert.Equal(t, "locationUri='/rest/enclosures/enc1'", query.Get("filter"))
+	assert.Equal(t, "position:asc", query.Get("sort"))
+	assert.Equal(t, 3, len(blades))
+	for i, b := range blades {
+		assert.Equal(t, i+1, b.GetBay())
+		assert.Equal(t, fmt.Sprintf("enc1, bay %d", i+1), b.Name)
+		enc, ok := b.GetEnclosureURI()
+		assert.True(t, ok)
+		assert.Equal(t, utils.Nstring("/rest/enclosures/enc1"), enc)
+	}
+
+	_, ok := ServerHardware{Name: "rack1"}.GetEnclosureURI()
+	assert.False(t, ok)
+	_, err = c.GetServerHardwareByEnclosure("")
+	assert.Error(t, err)
+}
+
+// forced power off test, PressAndHold is sent and rejections are returned
+func TestPowerOffForce(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	err = blade.PowerOffForce(WithWaitTime(0))
+	assert.NoError(t, err, "PowerOffForce threw error -> %s", err)
+	assert.Equal(t, "Off", b.GetState())
+	var sent []interface{}
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware/1/powerState" {
+			sent = append(sent, call.Options)
+		}
+	}
+	assert.Equal(t, []interface{}{PowerRequest{PowerState: "Off", PowerControl: "PressAndHold"}}, sent)
+
+	b.State = "On"
+	f.HandleStatus(rest.PUT, "/rest/server-hardware/1/powerState", 400, "PressAndHold is not supported")
+	err = blade.PowerOffForce(WithWaitTime(0))
+	var serr *rest.StatusError
+	assert.True(t, errors.As(err, &serr), "expected the appliance rejection, got %s", err)
+	assert.Equal(t, 400, serr.StatusCode)
+}
+
+// refresh server hardware test, stale power states are reconciled
+func TestRefreshServerHardware(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Unknown")
+	b.Actual = "On"
+
+	task, err := c.RefreshServerHardware("/rest/server-hardware/1", TaskWaitTime(0))
+	assert.NoError(t, err, "RefreshServerHardware threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	assert.Equal(t, "On", b.GetState())
+	var sent []interface{}
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware/1/refreshState" {
+			sent = append(sent, call.Options)
+		}
+	}
+	assert.Equal(t, []interface{}{ServerHardwareRefresh{RefreshState: "RefreshPending"}}, sent)
+
+	_, err = c.RefreshServerHardware("")
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+
+	// power tasks refresh when they see an unknown power state
+	b.State = "Unknown"
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	assert.NoError(t, pt.GetCurrentPowerState())
+	assert.Equal(t, P_UNKNOWN, pt.State)
+	assert.Equal(t, 1, b.Refreshes())
+
+	pt = pt.NewPowerTask(blade, WithWaitTime(0), WithRefreshOnUnknown())
+	assert.NoError(t, pt.GetCurrentPowerState())
+	assert.Equal(t, P_ON, pt.State)
+	assert.Equal(t, 2, b.Refreshes())
+
+	// without options the refresh task waits with the power defaults
+	SetDefaultPowerTimeout(3)
+	SetDefaultPowerWaitTime(0)
+	defer SetDefaultPowerTimeout(0)
+	defer SetDefaultPowerWaitTime(-1)
+	task, err = c.RefreshServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "RefreshServerHardware threw error -> %s", err)
+	assert.Equal(t, 3, task.Timeout)
+	assert.Equal(t, time.Duration(0), task.WaitTime)
+}
+
+// get server hardware by a name with a quote test, the filter value is escaped
+func TestGetServerHardwareByQuotedName(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "O'Brien's blade", "Off")
+	f.AddBlade("/rest/server-hardware/2", "O", "Off")
+
+	hw, err := c.GetServerHardwareByName("O'Brien's blade")
+	assert.NoError(t, err, "GetServerHardwareByName threw error -> %s", err)
+	assert.Equal(t, utils.Nstring("/rest/server-hardware/1"), hw.URI)
+	calls := f.Calls()
+	assert.Equal(t, []string{"name='O''Brien''s blade'"}, calls[len(calls)-1].Query["filter"])
+
+	_, err = c.GetServerHardwareBySerialNumber("OVTEST' OR name='O")
+	assert.True(t, errors.Is(err, ErrServerHardwareNotFound), "expected not found, got %s", err)
+	assert.Equal(t, "'it''s'", filterValue("it's"))
+}
+
+// TestServerHardwareInventory verify the inventory fields are read by GetServerHardware
+func TestServerHardwareInventory(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1", `{"uri":"/rest/server-hardware/1","name":"se05, bay 16",
+		"model":"ProLiant BL460c Gen9","mpModel":"iLO4","mpFirmwareVersion":"2.03 Nov 07 2014","processorCount":2,"memoryMb":262144}`)
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	assert.Equal(t, "ProLiant BL460c Gen9", blade.Model)
+	assert.Equal(t, "iLO4", blade.MpModel)
+	assert.Equal(t, "2.03 Nov 07 2014", blade.GetMpFirmwareVersion())
+	assert.Equal(t, 2, blade.ProcessorCount)
+	assert.Equal(t, 262144, blade.MemoryMb)
+}
+
+// TestGetServerHardwareConcurrent verify clones of a client read server hardware
+// concurrently, run with -race
+func TestGetServerHardwareConcurrent(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	for i := 1; i <= 4; i++ {
+		f.AddBlade(fmt.Sprintf("/rest/server-hardware/%d", i), fmt.Sprintf("enc1, bay %d", i), "Off")
+	}
+	var wg sync.WaitGroup
+	for i := 1; i <= 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := c.Clone()
+			blade, err := client.GetServerHardware(utils.Nstring(fmt.Sprintf("/rest/server-hardware/%d", i)))
+			assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+			assert.Equal(t, fmt.Sprintf("enc1, bay %d", i), blade.Name)
+			_, err = client.GetServerHardwareBySerialNumber("OVTESTenc1, bay " + strconv.Itoa(i))
+			assert.NoError(t, err, "GetServerHardwareBySerialNumber threw error -> %s", err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestGetServerHardwareEventual verify 404s are retried within the window, a missing
+// blade is not found once it passes and other errors aren't retried
+func TestGetServerHardwareEventual(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	reads := 0
+	f.Handle(rest.GET, "/rest/server-hardware/new", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		if reads++; reads <= 2 {
+			return nil, ovtest.StatusError(http.StatusNotFound, "not found")
+		}
+		return []byte(`{"uri":"/rest/server-hardware/new","name":"enc1, bay 3"}`), nil
+	})
+	blade, err := c.GetServerHardwareEventual("/rest/server-hardware/new", time.Second, time.Millisecond)
+	assert.NoError(t, err, "GetServerHardwareEventual threw error -> %s", err)
+	assert.Equal(t, "enc1, bay 3", blade.Name)
+	assert.Equal(t, 3, reads)
+
+	_, err = c.GetServerHardwareEventual("/rest/server-hardware/missing", 5*time.Millisecond, time.Millisecond)
+	assert.True(t, errors.Is(err, ErrServerHardwareNotFound), "expected not found, got %s", err)
+
+	f.HandleStatus(rest.GET, "/rest/server-hardware/broken", http.StatusBadRequest, "bad request")
+	_, err = c.GetServerHardwareEventual("/rest/server-hardware/broken", time.Second, time.Millisecond)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrServerHardwareNotFound))
+	broken := 0
+	for _, call := range f.Calls() {
+		if call.Path == "/rest/server-hardware/broken" {
+			broken++
+		}
+	}
+	assert.Equal(t, 1, broken)
+}
+
+// TestReadyForPower verify the blade is waited on until present, refreshed and in a
+// known power state, and reported not ready when it stays busy
+func TestReadyForPower(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	reads := 0
+	f.Handle(rest.GET, "/rest/server-hardware/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		switch reads++; reads {
+		case 1:
+			return nil, ovtest.StatusError(http.StatusNotFound, "not found")
+		case 2:
+			return []byte(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","powerState":"Unknown","refreshState":"Refreshing"}`), nil
+		case 3:
+			return []byte(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","powerState":"Unknown","refreshState":"NotRefreshing"}`), nil
+		}
+		return []byte(`{"uri":"/rest/server-hardware/1","name":"enc1, bay 1","powerState":"Off","refreshState":"NotRefreshing"}`), nil
+	})
+	assert.NoError(t, c.ReadyForPower("/rest/server-hardware/1", time.Second, time.Millisecond))
+	assert.Equal(t, 4, reads)
+
+	b := f.AddBlade("/rest/server-hardware/2", "enc1, bay 2", "On")
+	b.HardwareState = "ApplyingProfile"
+	err := c.ReadyForPower("/rest/server-hardware/2", 5*time.Millisecond, time.Millisecond)
+	assert.True(t, errors.Is(err, ErrBladeNotReady), "expected not ready, got %s", err)
+	assert.Contains(t, err.Error(), "ApplyingProfile")
+
+	f.HandleStatus(rest.GET, "/rest/server-hardware/3", http.StatusInternalServerError, "down")
+	err = c.ReadyForPower("/rest/server-hardware/3", time.Second, time.Millisecond)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrBladeNotReady))
+}
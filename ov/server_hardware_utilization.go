@@ -0,0 +1,158 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// utilizationDateFormat - date format the appliance uses for utilization filters
+const utilizationDateFormat = "2006-01-02T15:04:05.000Z"
+
+// UtilizationQuery - optional query for GetServerHardwareUtilization
+type UtilizationQuery struct {
+	Fields    []string  // metrics to get, AmbientTemperature, AveragePower, CpuUtilization, ..., all when empty
+	Refresh   bool      // ask the appliance to refresh the samples from the hardware
+	StartDate time.Time // oldest sample to get, the appliance default when zero
+	EndDate   time.Time // newest sample to get, the appliance default when zero
+}
+
+// MetricSample - a single [timestamp, value] utilization sample
+type MetricSample struct {
+	Time  string  // "2015-09-07T04:55:00.000Z"
+	Value float64 // 22
+}
+
+// UnmarshalJSON - samples are sent as a two element array
+func (m *MetricSample) UnmarshalJSON(b []byte) error {
+	var sample []interface{}
+	if err := json.Unmarshal(b, &sample); err != nil {
+		return err
+	}
+	if len(sample) != 2 {
+		return fmt.Errorf("Error metric sample needs a timestamp and value, got %s", b)
+	}
+	switch ts := sample[0].(type) {
+	case string:
+		m.Time = ts
+	case float64:
+		// some api versions send epoch milliseconds
+		m.Time = time.Unix(0, int64(ts)*int64(time.Millisecond)).UTC().Format(utilizationDateFormat)
+	}
+	if v, ok := sample[1].(float64); ok {
+		m.Value = v
+	}
+	return nil
+}
+
+// Metric - samples for one utilization metric
+type Metric struct {
+	MetricName     string         `json:"metricName,omitempty"`     // "metricName": "AveragePower",
+	MetricCapacity float64        `json:"metricCapacity,omitempty"` // "metricCapacity": 460,
+	MetricSamples  []MetricSample `json:"metricSamples,omitempty"`  // "metricSamples": [["2015-09-07T04:55:00.000Z", 141]]
+}
+
+// Average - average value of the samples, 0 when there are none
+func (m Metric) Average() float64 {
+	if len(m.MetricSamples) == 0 {
+		return 0
+	}
+	var total float64
+	for _, s := range m.MetricSamples {
+		total += s.Value
+	}
+	return total / float64(len(m.MetricSamples))
+}
+
+// Latest - newest sample, the appliance sends samples newest first
+func (m Metric) Latest() (MetricSample, bool) {
+	if len(m.MetricSamples) == 0 {
+		return MetricSample{}, false
+	}
+	return m.MetricSamples[0], true
+}
+
+// ServerHardwareUtilization - utilization samples for a server hardware
+type ServerHardwareUtilization struct {
+	Resolution       int           `json:"resolution,omitempty"`       // "resolution": 300000,
+	SliceStartTime   string        `json:"sliceStartTime,omitempty"`   // "sliceStartTime": "2015-09-07T04:50:00.000Z",
+	SliceEndTime     string        `json:"sliceEndTime,omitempty"`     // "sliceEndTime": "2015-09-07T04:55:00.000Z",
+	NewestSampleTime string        `json:"newestSampleTime,omitempty"` // "newestSampleTime": "2015-09-07T04:55:00.000Z",
+	OldestSampleTime string        `json:"oldestSampleTime,omitempty"` // "oldestSampleTime": "2015-09-04T05:00:00.000Z",
+	IsFresh          bool          `json:"isFresh,omitempty"`          // "isFresh": true,
+	RefreshTaskURI   utils.Nstring `json:"refreshTaskUri,omitempty"`   // "refreshTaskUri": null,
+	MetricList       []Metric      `json:"metricList,omitempty"`       // "metricList": [],
+	URI              utils.Nstring `json:"uri,omitempty"`              // "uri": "/rest/server-hardware/30373237-3132-4D32-3235-303930524D57/utilization"
+}
+
+// GetMetric - get the metric named name, false when it wasn't returned
+func (u ServerHardwareUtilization) GetMetric(name string) (Metric, bool) {
+	for _, m := range u.MetricList {
+		if strings.EqualFold(m.MetricName, name) {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// GetServerHardwareUtilization - get the utilization samples of the server hardware at uri
+func (c *OVClient) GetServerHardwareUtilization(uri utils.Nstring, query UtilizationQuery) (ServerHardwareUtilization, error) {
+	var (
+		utilization ServerHardwareUtilization
+		q           = make(map[string]interface{})
+		filters     []string
+	)
+	if uri.IsNil() {
+		return utilization, ErrNoBladeHardware
+	}
+	if len(query.Fields) > 0 {
+		q["fields"] = strings.Join(query.Fields, ",")
+	}
+	if query.Refresh {
+		q["refresh"] = "true"
+	}
+	if !query.StartDate.IsZero() {
+		filters = append(filters, "startDate="+query.StartDate.UTC().Format(utilizationDateFormat))
+	}
+	if !query.EndDate.IsZero() {
+		filters = append(filters, "endDate="+query.EndDate.UTC().Format(utilizationDateFormat))
+	}
+	if len(filters) > 0 {
+		q["filter"] = filters
+	}
+
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	c.SetQueryString(q)
+	defer c.SetQueryString(make(map[string]interface{}))
+
+	// rest call, samples can be large, they are decoded as they are read
+	if err := c.RestAPICallDecode(context.Background(), rest.GET, uri.String()+"/utilization", nil, &utilization); err != nil {
+		return utilization, err
+	}
+	log.Debugf("GetServerHardwareUtilization %s, %d metrics", uri, len(utilization.MetricList))
+	return utilization, nil
+}
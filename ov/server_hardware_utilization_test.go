@@ -0,0 +1,58 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// get server hardware utilization test
+func TestGetServerHardwareUtilization(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/server-hardware/1/utilization", `{"resolution":300000,"isFresh":true,"refreshTaskUri":null,
+		"newestSampleTime":"2015-09-07T04:55:00.000Z","oldestSampleTime":"2015-09-07T04:45:00.000Z",
+		"metricList":[
+			{"metricName":"AveragePower","metricCapacity":460,"metricSamples":[["2015-09-07T04:55:00.000Z",150],["2015-09-07T04:50:00.000Z",130],[1441601100000,140]]},
+			{"metricName":"AmbientTemperature","metricCapacity":50,"metricSamples":[]}],
+		"uri":"/rest/server-hardware/1/utilization"}`)
+
+	u, err := c.GetServerHardwareUtilization("/rest/server-hardware/1", UtilizationQuery{
+		Fields:    []string{"AveragePower", "AmbientTemperature"},
+		Refresh:   true,
+		StartDate: time.Date(2015, 9, 7, 4, 45, 0, 0, time.UTC),
+		EndDate:   time.Date(2015, 9, 7, 4, 55, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err, "GetServerHardwareUtilization threw error -> %s", err)
+	calls := f.Calls()
+	query := calls[len(calls)-1].Query
+	assert.Equal(t, "AveragePower,AmbientTemperature", query.Get("fields"))
+	assert.Equal(t, "true", query.Get("refresh"))
+	assert.Equal(t, []string{"startDate=2015-09-07T04:45:00.000Z", "endDate=2015-09-07T04:55:00.000Z"}, query["filter"])
+	assert.Equal(t, 0, len(c.Option.Query))
+
+	assert.True(t, u.IsFresh)
+	assert.Equal(t, 2, len(u.MetricList))
+	power, ok := u.GetMetric("averagepower")
+	assert.True(t, ok)
+	assert.Equal(t, 140.0, power.Average())
+	latest, ok := power.Latest()
+	assert.True(t, ok)
+	assert.Equal(t, MetricSample{Time: "2015-09-07T04:55:00.000Z", Value: 150}, latest)
+	assert.Equal(t, "2015-09-07T04:45:00.000Z", power.MetricSamples[2].Time)
+
+	temp, ok := u.GetMetric("AmbientTemperature")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, temp.Average())
+	_, ok = temp.Latest()
+	assert.False(t, ok)
+	_, ok = u.GetMetric("CpuUtilization")
+	assert.False(t, ok)
+
+	_, err = c.GetServerHardwareUtilization("", UtilizationQuery{})
+	assert.True(t, errors.Is(err, ErrNoBladeHardware))
+}
@@ -22,7 +22,6 @@ import (
 
 	"github.com/HewlettPackard/oneview-golang/liboneview"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // introduced in v200 for oneview, new v2 hardware attributes
@@ -18,14 +18,17 @@ limitations under the License.
 package ov
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // AssociatedResource associated resource
@@ -42,7 +45,7 @@ type TaskState int
 const (
 	T_COMPLETED TaskState = 1 + iota
 	T_ERROR
-	T_INERRUPTED
+	T_INTERRUPTED
 	T_KILLED
 	T_NEW
 	T_PENDING
@@ -53,6 +56,8 @@ const (
 	T_TERMINATED
 	T_UNKNOWN
 	T_WARNING
+	T_CANCELLING
+	T_CANCELLED
 )
 
 var taskstate = [...]string{
@@ -69,13 +74,60 @@ var taskstate = [...]string{
 	"Terminated",  // Terminated Task has been terminated.
 	"Unknown",     // Unknown State of task is unknown.
 	"Warning",     // Warning Task has terminated with a warning.
+	"Cancelling",  // Cancelling Task is being cancelled.
+	"Cancelled",   // Cancelled Task has been cancelled.
 }
 
+// T_INERRUPTED - Deprecated: misspelling of T_INTERRUPTED, kept for compatibility
+const T_INERRUPTED = T_INTERRUPTED
+
+// taskstateupper - taskstate upper cased once for Equal and ParseTaskState
+var taskstateupper = func() (upper [len(taskstate)]string) {
+	for i, ts := range taskstate {
+		upper[i] = strings.ToUpper(ts)
+	}
+	return upper
+}()
+
+// ErrUnknownTaskState - the task state isn't one the appliance documents
+var ErrUnknownTaskState = errors.New("Un-known task state")
+
 // String for type
 func (ts TaskState) String() string { return taskstate[ts-1] }
 
-// Equal for type
-func (ts TaskState) Equal(s string) bool { return (strings.ToUpper(s) == strings.ToUpper(ts.String())) }
+// Equal - case insensitive match of s, ignoring surrounding whitespace
+func (ts TaskState) Equal(s string) bool {
+	if ts < 1 || int(ts) > len(taskstateupper) {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(s), taskstateupper[ts-1])
+}
+
+// IsTerminal - true when a task in this state won't change state again
+func (ts TaskState) IsTerminal() bool {
+	switch ts {
+	case T_COMPLETED, T_ERROR, T_INTERRUPTED, T_KILLED, T_TERMINATED, T_WARNING, T_CANCELLED:
+		return true
+	}
+	return false
+}
+
+// IsQueued - true for the states of a task waiting to start, New and Pending
+func (ts TaskState) IsQueued() bool {
+	return ts == T_NEW || ts == T_PENDING
+}
+
+// ParseTaskState - get the TaskState for a task state reported by the appliance,
+// returns T_UNKNOWN and ErrUnknownTaskState when s isn't a known task state
+func ParseTaskState(s string) (TaskState, error) {
+	t := strings.TrimSpace(s)
+	for i, ts := range taskstateupper {
+		if strings.EqualFold(t, ts) {
+			return TaskState(i + 1), nil
+		}
+	}
+	return T_UNKNOWN, fmt.Errorf("%w %q", ErrUnknownTaskState, s)
+}
 
 // TaskType - task type
 type TaskType int
@@ -98,12 +150,30 @@ func (tt TaskType) String() string { return tasktype[tt-1] }
 // Equal type
 func (tt TaskType) Equal(s string) bool { return (strings.ToUpper(s) == strings.ToUpper(tt.String())) }
 
+// BackoffPolicy - how the wait time between task checks grows
+type BackoffPolicy int
+
+const (
+	B_FIXED BackoffPolicy = iota
+	B_LINEAR
+	B_EXPONENTIAL
+)
+
+var backoffpolicy = [...]string{
+	"Fixed",       // Fixed wait WaitTime between every check.
+	"Linear",      // Linear wait grows by WaitTime after every check.
+	"Exponential", // Exponential wait doubles after every check.
+}
+
+// String for type
+func (b BackoffPolicy) String() string { return backoffpolicy[b] }
+
 // TaskError struct
 type TaskError struct {
 	Data               map[string]interface{} `json:"data,omitempty"`               // "data":{},
 	ErrorCode          string                 `json:"errorCode,omitempty"`          // "errorCode":"MacTypeDiffGlobalMacType",
 	Details            string                 `json:"details,omitempty"`            // "details":"",
-	NestedErrors       []string               `json:"nestedErrors,omitempty"`       // "nestedErrors":[],
+	NestedErrors       []TaskError            `json:"nestedErrors,omitempty"`       // "nestedErrors":[],
 	Message            string                 `json:"message,omitempty"`            // "message":"When macType is not user defined, mac type should be same as the global Mac assignment Virtual."
 	ErrorSource        utils.Nstring          `json:"errorSource,omitempty"`        // "errorSource":null,
 	RecommendedActions []string               `json:"recommendedActions,omitempty"` // "recommendedActions":["Verify parameters and try again."],
@@ -137,6 +207,7 @@ type Task struct {
 	TaskState               string             `json:"taskState,omitempty"`               // "taskState": "New",
 	TaskStatus              string             `json:"taskStatus,omitempty"`              // "taskStatus": "Power off Server: se05, bay 16",
 	TaskType                string             `json:"taskType,omitempty"`
+	IsCancellable           bool               `json:"isCancellable,omitempty"` // "isCancellable": false,
 	TotalSteps              int                `json:"totalSteps,omitempty"`    // "totalSteps": 0,
 	UserInitiated           bool               `json:"userInitiated,omitempty"` // "userInitiated": true,
 	Name                    string             `json:"name,omitempty"`          // "name": "Power off",
@@ -148,7 +219,22 @@ type Task struct {
 	TaskIsDone              bool               // when true, task are done
 	Timeout                 int                // time before timeout on Executor
 	WaitTime                time.Duration      // time between task checks
-	Client                  *OVClient
+	Backoff                 BackoffPolicy      // how WaitTime grows between task checks, default B_FIXED
+	MaxWaitTime             time.Duration      // ceiling for the backoff wait time, no ceiling when 0
+	MinWaitTime             time.Duration      // floor for the wait time after MaxWaitTime and Jitter, no floor when 0
+	FailStates              []TaskState        `json:"-"` // terminal states treated as failure, DefaultTaskFailStates when nil
+	MaxTimeout              int                // extend Timeout up to MaxTimeout checks while the task progresses, no extension when 0
+	StallChecks             int                // checks without progress before the task times out early, never when 0
+	PendingTimeout          int                // checks the task may stay New or Pending, not counted in Timeout, counted in Timeout when 0
+	Batcher                 *TaskBatcher       `json:"-"` // optional, checks the task in calls shared with other tasks
+	Jitter                  float64            // fraction of the wait time randomly added or removed, spreads the checks of many tasks
+	Client                  TaskClient
+	RawExtras               map[string]json.RawMessage `json:"-"` // fields the task was sent that aren't known, see CaptureRawExtras
+}
+
+// TaskClient - the calls a Task makes to check on itself, satisfied by *OVClient
+type TaskClient interface {
+	RestAPICallContext(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error)
 }
 
 // TaskServer Example:
@@ -169,8 +255,8 @@ func (t *Task) NewProfileTask(c *OVClient) *Task {
 		URI:      "",
 		Name:     "",
 		Owner:    "",
-		Timeout:  144, // default 24min
-		WaitTime: 10}  // default 10sec, impacts Timeout
+		Timeout:  144,              // default 24min
+		WaitTime: 10 * time.Second} // default 10sec, impacts Timeout
 }
 
 // ResetTask - reset the power task back to off
@@ -183,34 +269,442 @@ func (t *Task) ResetTask() {
 
 // GetCurrentTaskStatus - Get the current status
 func (t *Task) GetCurrentTaskStatus() error {
+	return t.GetCurrentTaskStatusContext(context.Background())
+}
+
+// GetCurrentTaskStatusContext - Get the current status, the request is bound to ctx
+func (t *Task) GetCurrentTaskStatusContext(ctx context.Context) error {
 	log.Debugf("Working on getting current task status")
 	var (
 		uri = t.URI
 	)
 	if uri != "" {
-		log.Debugf(uri.String())
-		data, err := t.Client.RestAPICall(rest.GET, uri.String(), nil)
+		log.Debugf("task uri: %s", uri)
+		var (
+			data []byte
+			err  error
+		)
+		if t.Batcher != nil {
+			data, err = t.Batcher.getTaskData(ctx, uri.String())
+		} else {
+			data, err = t.Client.RestAPICallContext(ctx, rest.GET, uri.String(), nil)
+		}
 		if err != nil {
 			return err
 		}
 		log.Debugf("data: %s", data)
+		if err := rest.CheckJSON(data); err != nil {
+			return fmt.Errorf("Error getting task %s: %w", uri, err)
+		}
 		if err := json.Unmarshal([]byte(data), &t); err != nil {
 			return err
 		}
 	} else {
 		log.Debugf("Unable to get current task, no URI found")
 	}
+	// task errors fail the check, with the summary and errors.As of a TaskFailedError
 	if len(t.TaskErrors) > 0 {
-		var errmsg string
-		errmsg = ""
-		for _, te := range t.TaskErrors {
-			errmsg += te.Message + " \n" + strings.Join(te.RecommendedActions, " ")
-		}
-		return errors.New(errmsg)
+		return newTaskFailedError(*t)
 	}
 	return nil
 }
 
+// maxBackoffWaitTime - ceiling for a growing wait time when MaxWaitTime isn't set,
+// keeps the doubling from overflowing
+const maxBackoffWaitTime = time.Hour
+
+// GetWaitTime - get the time to wait before the given task check (starting at 0),
+// applying the Backoff policy and MaxWaitTime ceiling to WaitTime, then Jitter,
+// then the MinWaitTime floor
+func (t *Task) GetWaitTime(check int) time.Duration {
+	ceiling := t.MaxWaitTime
+	if ceiling <= 0 {
+		ceiling = maxBackoffWaitTime
+	}
+	wait := t.WaitTime
+	switch t.Backoff {
+	case B_LINEAR:
+		if wait > 0 && time.Duration(check+1) > ceiling/wait {
+			wait = ceiling
+		} else {
+			wait = t.WaitTime * time.Duration(check+1)
+		}
+	case B_EXPONENTIAL:
+		for i := 0; i < check && wait > 0 && wait < ceiling; i++ {
+			wait *= 2
+		}
+	}
+	if t.Backoff != B_FIXED && wait > ceiling {
+		wait = ceiling
+	}
+	if t.MaxWaitTime > 0 && wait > t.MaxWaitTime {
+		wait = t.MaxWaitTime
+	}
+	wait = jitter(wait, t.Jitter)
+	if wait < t.MinWaitTime {
+		wait = t.MinWaitTime
+	}
+	return wait
+}
+
+// jitter - move wait randomly by up to fraction of it either way, fraction is
+// capped to 1 so the wait never goes negative
+func jitter(wait time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || wait <= 0 {
+		return wait
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	return wait + time.Duration((rand.Float64()*2-1)*fraction*float64(wait))
+}
+
+var (
+	// ErrTaskTimeout - the task did not complete within the checks allowed
+	ErrTaskTimeout = errors.New("Task timed out")
+	// ErrTaskPendingTimeout - the task stayed queued past its PendingTimeout, the
+	// appliance is too busy to start it
+	ErrTaskPendingTimeout = errors.New("Task still pending")
+	// ErrTaskFailed - the task ended in a failure state or reported task errors
+	ErrTaskFailed = errors.New("Task failed")
+	// ErrTaskNotFound - the task doesn't exist, or was purged from the appliance
+	ErrTaskNotFound = errors.New("Task not found")
+)
+
+// DefaultTaskFailStates - terminal task states treated as failure, a task
+// ending in T_WARNING is done unless T_WARNING is added to Task.FailStates
+var DefaultTaskFailStates = []TaskState{T_ERROR, T_INTERRUPTED, T_KILLED, T_TERMINATED, T_CANCELLED}
+
+// TaskFailedError - error for a task that failed, carries the task errors
+type TaskFailedError struct {
+	URI    string
+	Name   string
+	State  string
+	Errors []TaskError
+}
+
+// Error - message with the task errors and their recommended actions
+func (e *TaskFailedError) Error() string {
+	msg := fmt.Sprintf("Task %s, %s, failed with state %s", e.Name, e.URI, e.State)
+	if summary := summarizeTaskErrors(e.Errors); summary != "" {
+		msg += ": " + summary
+	}
+	return msg
+}
+
+// Is - match ErrTaskFailed
+func (e *TaskFailedError) Is(target error) bool { return target == ErrTaskFailed }
+
+// newTaskFailedError - get a TaskFailedError for the task
+func newTaskFailedError(t Task) *TaskFailedError {
+	return &TaskFailedError{URI: t.URI.String(), Name: t.Name, State: t.TaskState, Errors: t.Errors()}
+}
+
+// Errors - the task errors with their nested errors flattened in after them
+func (t *Task) Errors() []TaskError {
+	var errs []TaskError
+	var flatten func([]TaskError)
+	flatten = func(tes []TaskError) {
+		for _, te := range tes {
+			nested := te.NestedErrors
+			te.NestedErrors = nil
+			errs = append(errs, te)
+			flatten(nested)
+		}
+	}
+	flatten(t.TaskErrors)
+	return errs
+}
+
+// ErrorSummary - one line with the message, error code and recommended
+// actions of every task error, empty when the task has none
+func (t *Task) ErrorSummary() string {
+	return summarizeTaskErrors(t.Errors())
+}
+
+// summarizeTaskErrors - join flattened task errors for logs and error messages
+func summarizeTaskErrors(tes []TaskError) string {
+	var parts []string
+	for _, te := range tes {
+		msg := te.Message
+		if msg == "" {
+			msg = te.Details
+		}
+		if te.ErrorCode != "" {
+			msg += " (" + te.ErrorCode + ")"
+		}
+		if len(te.RecommendedActions) > 0 {
+			msg += " " + strings.Join(te.RecommendedActions, " ")
+		}
+		parts = append(parts, strings.TrimSpace(msg))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GetTaskState - get the typed state of the task, T_UNKNOWN when it isn't known
+func (t *Task) GetTaskState() TaskState {
+	ts, _ := ParseTaskState(t.TaskState)
+	return ts
+}
+
+// IsFailed - true when the task is in one of its FailStates
+func (t *Task) IsFailed() bool {
+	states := t.FailStates
+	if states == nil {
+		states = DefaultTaskFailStates
+	}
+	for _, fs := range states {
+		if fs.Equal(t.TaskState) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinished - true when the task completed, or ended with a warning that isn't a failure
+func (t *Task) isFinished() bool {
+	return t.URI != "" && (T_COMPLETED.Equal(t.TaskState) || (T_WARNING.Equal(t.TaskState) && !t.IsFailed()))
+}
+
+// taskPoller - a task that can be refreshed and read between checks
+type taskPoller interface {
+	GetCurrentTaskStatusContext(ctx context.Context) error
+	GetWaitTime(check int) time.Duration
+	getTask() Task
+	setTaskIsDone()
+}
+
+// getTask - get a copy of the task
+func (t *Task) getTask() Task { return *t }
+
+// setTaskIsDone - mark the task as done
+func (t *Task) setTaskIsDone() { t.TaskIsDone = true }
+
+// TaskProgress - percent complete history of a task, used to estimate completion
+// and to tell a slow task that is still progressing from a stalled one
+type TaskProgress struct {
+	times    []time.Time
+	percents []int
+}
+
+// Add - record the percent complete seen at time at
+func (p *TaskProgress) Add(percent int, at time.Time) {
+	p.times = append(p.times, at)
+	p.percents = append(p.percents, percent)
+}
+
+// Progressing - true when the last check saw more percent complete than the one before
+func (p *TaskProgress) Progressing() bool {
+	n := len(p.percents)
+	return n > 1 && p.percents[n-1] > p.percents[n-2]
+}
+
+// Stalled - true when percent complete hasn't changed for the last checks checks
+func (p *TaskProgress) Stalled(checks int) bool {
+	n := len(p.percents)
+	if checks <= 0 || n <= checks {
+		return false
+	}
+	for _, percent := range p.percents[n-checks-1 : n-1] {
+		if percent != p.percents[n-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimateRemaining - estimate the time left from the average rate of progress,
+// false when no progress has been seen yet
+func (p *TaskProgress) EstimateRemaining() (time.Duration, bool) {
+	n := len(p.percents)
+	if n < 2 {
+		return 0, false
+	}
+	done := p.percents[n-1] - p.percents[0]
+	elapsed := p.times[n-1].Sub(p.times[0])
+	if done <= 0 || elapsed <= 0 {
+		return 0, false
+	}
+	left := 100 - p.percents[n-1]
+	if left <= 0 {
+		return 0, true
+	}
+	return time.Duration(int64(elapsed) * int64(left) / int64(done)), true
+}
+
+// pollTask - check the task until it is done, fails, the timeout checks are made or
+// ctx is done, status is called with the task after every check. The timeout is
+// extended up to the task MaxTimeout while it progresses, and cut short when it
+// stalls for StallChecks. Returns the checks made and the timeout that applied.
+func pollTask(ctx context.Context, p taskPoller, timeout int, status func(t Task)) (int, int, error) {
+	var (
+		checks   = 0
+		pending  = 0
+		progress TaskProgress
+	)
+	for checks < timeout {
+		if p.getTask().TaskIsDone {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return checks, timeout, err
+		}
+		if err := p.GetCurrentTaskStatusContext(ctx); err != nil {
+			if t := p.getTask(); len(t.TaskErrors) > 0 {
+				return checks, timeout, newTaskFailedError(t)
+			}
+			return checks, timeout, err
+		}
+		t := p.getTask()
+		if status != nil {
+			status(t)
+		}
+		if t.URI != "" && t.IsFailed() {
+			log.Warnf("Task, %s, failed with state %s", t.Name, t.TaskState)
+			return checks, timeout, newTaskFailedError(t)
+		}
+		// checks of a queued task count against PendingTimeout instead of timeout
+		queued := t.PendingTimeout > 0 && t.URI != "" && !t.isFinished() && t.GetTaskState().IsQueued()
+		if t.isFinished() {
+			p.setTaskIsDone()
+		} else if queued {
+			pending++
+			if pending >= t.PendingTimeout {
+				log.Warnf("Task, %s, still %s after %d checks", t.Name, t.TaskState, pending)
+				return checks, timeout, fmt.Errorf("%w, %s %s after %d checks", ErrTaskPendingTimeout, t.URI, t.TaskState, pending)
+			}
+		} else if t.URI != "" {
+			progress.Add(t.ComputedPercentComplete, time.Now())
+			if progress.Stalled(t.StallChecks) {
+				log.Warnf("Task, %s, stalled at %d%% for %d checks", t.Name, t.ComputedPercentComplete, t.StallChecks)
+				return timeout, timeout, nil
+			}
+			if checks+1 >= timeout && timeout < t.MaxTimeout && progress.Progressing() {
+				timeout++
+				remaining, _ := progress.EstimateRemaining()
+				log.Infof("Task, %s, still progressing at %d%%, about %s remaining, extending timeout to %d checks",
+					t.Name, t.ComputedPercentComplete, remaining, timeout)
+			}
+		}
+
+		// wait time before next check
+		select {
+		case <-ctx.Done():
+			return checks, timeout, ctx.Err()
+		case <-time.After(p.GetWaitTime(checks)):
+		}
+		if !queued {
+			checks++
+		}
+	}
+	return checks, timeout, nil
+}
+
+// TaskOption - option for a task waited on with WaitForTask
+type TaskOption func(*Task)
+
+// TaskTimeout - number of task checks before the task times out
+func TaskTimeout(timeout int) TaskOption {
+	return func(t *Task) {
+		t.Timeout = timeout
+	}
+}
+
+// TaskWaitTime - time to wait between task checks
+func TaskWaitTime(wait time.Duration) TaskOption {
+	return func(t *Task) {
+		t.WaitTime = wait
+	}
+}
+
+// TaskPendingTimeout - number of checks the task may stay queued, New or Pending,
+// before it fails with ErrTaskPendingTimeout, they aren't counted in its timeout
+func TaskPendingTimeout(checks int) TaskOption {
+	return func(t *Task) {
+		t.PendingTimeout = checks
+	}
+}
+
+// TaskSoftTimeout - keep extending the timeout, up to max checks, while the task progresses
+func TaskSoftTimeout(max int) TaskOption {
+	return func(t *Task) {
+		t.MaxTimeout = max
+	}
+}
+
+// TaskJitter - move each wait between checks randomly by up to fraction of WaitTime
+func TaskJitter(fraction float64) TaskOption {
+	return func(t *Task) {
+		t.Jitter = fraction
+	}
+}
+
+// TaskStallChecks - time out once percent complete hasn't changed for checks checks
+func TaskStallChecks(checks int) TaskOption {
+	return func(t *Task) {
+		t.StallChecks = checks
+	}
+}
+
+// GetTask - get the task at uri, returns ErrTaskNotFound when the appliance
+// no longer has it
+func (c *OVClient) GetTask(uri string) (*Task, error) {
+	if uri == "" {
+		return nil, errors.New("Unable to get task, no URI found")
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+
+	data, err := c.RestAPICall(rest.GET, uri, nil)
+	if err != nil {
+		var serr *rest.StatusError
+		if errors.As(err, &serr) && serr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w, %s: %v", ErrTaskNotFound, uri, err)
+		}
+		return nil, err
+	}
+
+	log.Debugf("GetTask %s", data)
+	task := &Task{}
+	if err := json.Unmarshal([]byte(data), task); err != nil {
+		return nil, err
+	}
+	task.Client = c
+	return task, nil
+}
+
+// WaitForTask - poll the task at taskURI until it completes, checking up to
+// timeout times wait apart, returns the last status of the task
+func (c *OVClient) WaitForTask(taskURI string, timeout int, wait time.Duration, opts ...TaskOption) (*Task, error) {
+	return c.WaitForTaskContext(context.Background(), taskURI, timeout, wait, opts...)
+}
+
+// WaitForTaskContext - WaitForTask bound to ctx
+func (c *OVClient) WaitForTaskContext(ctx context.Context, taskURI string, timeout int, wait time.Duration, opts ...TaskOption) (*Task, error) {
+	t := &Task{Client: c, URI: utils.NewNstring(taskURI), Timeout: timeout, WaitTime: wait}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if taskURI == "" {
+		return t, errors.New("Unable to wait for task, no URI found")
+	}
+	_, timeout, err := pollTask(ctx, t, t.Timeout, func(t Task) {
+		log.Infof("Waiting on, %s, %d%%, %s", t.Name, t.ComputedPercentComplete, t.GetLastStatusUpdate())
+	})
+	if err != nil {
+		return t, err
+	}
+	if !t.TaskIsDone {
+		log.Warnf("Task timed out, %s.", taskURI)
+		return t, fmt.Errorf("%w, %s after %d checks", ErrTaskTimeout, taskURI, timeout)
+	}
+	if t.Name != "" {
+		log.Infof("Task, %s, completed", t.Name)
+	}
+	return t, nil
+}
+
 // GetLastStatusUpdate - get last detail updates from task
 func (t *Task) GetLastStatusUpdate() string {
 	if len(t.ProgressUpdates) > 0 {
@@ -228,6 +722,18 @@ func (t *Task) GetLastStatusUpdate() string {
 	return t.TaskStatus
 }
 
+// ProgressUpdatesSince - the progress updates after the first n, the steps the
+// appliance reported since a check that had n of them
+func (t *Task) ProgressUpdatesSince(n int) []ProgressUpdate {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(t.ProgressUpdates) {
+		return nil
+	}
+	return t.ProgressUpdates[n:]
+}
+
 // Wait - wait on task to complete
 func (t *Task) Wait() error {
 	var (
@@ -244,7 +750,11 @@ func (t *Task) Wait() error {
 			t.TaskIsDone = true
 			return err
 		}
-		if t.URI != "" && T_COMPLETED.Equal(t.TaskState) {
+		if t.URI != "" && t.IsFailed() {
+			t.TaskIsDone = true
+			return newTaskFailedError(*t)
+		}
+		if t.isFinished() {
 			t.TaskIsDone = true
 		}
 		if t.URI != "" {
@@ -252,11 +762,11 @@ func (t *Task) Wait() error {
 			log.Debugf("Waiting on, %s, %d%%, %s, %d, %d", t.Name, t.ComputedPercentComplete, t.GetLastStatusUpdate(), currenttime, t.ExpectedDuration)
 			log.Infof("Waiting on, %s, %d%%, %s", t.Name, t.ComputedPercentComplete, t.GetLastStatusUpdate())
 		} else {
-			log.Info("Waiting on task creation.")
+			log.Infof("Waiting on task creation.")
 		}
 
 		// wait time before next check
-		time.Sleep(time.Millisecond * (1000 * t.WaitTime)) // wait 10sec before checking the status again
+		time.Sleep(t.GetWaitTime(currenttime)) // wait 10sec before checking the status again
 		currenttime++
 		if t.Timeout < t.ExpectedDuration {
 			t.Timeout = t.ExpectedDuration
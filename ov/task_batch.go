@@ -0,0 +1,240 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+const (
+	// DefaultTaskBatchWindow - longest a task check waits for others to share its call
+	DefaultTaskBatchWindow = 250 * time.Millisecond
+	// DefaultTaskBatchSize - most task uris asked for in a single call
+	DefaultTaskBatchSize = 50
+)
+
+// TaskList - a page of the task collection
+type TaskList struct {
+	Type        string        `json:"type,omitempty"`        // "type": "TaskResourceCollectionV2",
+	Count       int           `json:"count,omitempty"`       // "count": 2,
+	NextPageURI utils.Nstring `json:"nextPageUri,omitempty"` // "nextPageUri": null,
+	PrevPageURI utils.Nstring `json:"prevPageUri,omitempty"` // "prevPageUri": null,
+	Start       int           `json:"start,omitempty"`       // "start": 0,
+	Total       int           `json:"total,omitempty"`       // "total": 2,
+	URI         string        `json:"uri,omitempty"`         // "uri": "/rest/tasks?filter=uri='/rest/tasks/1'&start=0&count=2"
+	Members     []Task        `json:"members,omitempty"`     // "members": []
+}
+
+// GetTasks - get the tasks matching all filters, following every page
+func (c *OVClient) GetTasks(filters []string, sort string) (TaskList, error) {
+	var tasklist TaskList
+	members, err := c.getTaskMembers(context.Background(), filters, sort)
+	if err != nil {
+		return tasklist, err
+	}
+	for _, data := range members {
+		t := Task{Client: c}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return tasklist, err
+		}
+		tasklist.Members = append(tasklist.Members, t)
+	}
+	tasklist.Count = len(tasklist.Members)
+	tasklist.Total = tasklist.Count
+	return tasklist, nil
+}
+
+// getTaskMembers - get the raw json of the tasks matching filters, raw so the
+// members can be unmarshalled over existing tasks like a single task get
+func (c *OVClient) getTaskMembers(ctx context.Context, filters []string, sort string) ([]json.RawMessage, error) {
+	var (
+		uri     = "/rest/tasks"
+		q       = make(map[string]interface{})
+		members []json.RawMessage
+	)
+	if len(filters) > 0 {
+		q["filter"] = filters
+	}
+	if sort != "" {
+		q["sort"] = sort
+	}
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	defer c.SetQueryString(make(map[string]interface{}))
+
+	for uri != "" {
+		var page struct {
+			NextPageURI utils.Nstring     `json:"nextPageUri,omitempty"`
+			Members     []json.RawMessage `json:"members,omitempty"`
+		}
+		c.SetQueryString(q)
+		data, err := c.RestAPICallContext(ctx, rest.GET, uri, nil)
+		if err != nil {
+			return members, err
+		}
+		log.Debugf("GetTasks %s", data)
+		if err := json.Unmarshal([]byte(data), &page); err != nil {
+			return members, err
+		}
+		members = append(members, page.Members...)
+		if uri, q, err = splitPageURI(page.NextPageURI); err != nil {
+			return members, err
+		}
+	}
+	return members, nil
+}
+
+// TaskBatcher - gathers the task checks of many waiters into a single task
+// collection call, each waiter still gets its own task back on its own channel
+type TaskBatcher struct {
+	Window time.Duration // time a check waits for others to join its call
+	Size   int           // most task uris in one call, DefaultTaskBatchSize when 0
+
+	client  *OVClient
+	mu      sync.Mutex
+	callmu  sync.Mutex
+	pending map[string][]chan taskBatchResult
+	polling bool
+}
+
+type taskBatchResult struct {
+	data []byte
+	err  error
+}
+
+// NewTaskBatcher - get a batcher making its calls with a clone of the client
+func (c *OVClient) NewTaskBatcher(window time.Duration) *TaskBatcher {
+	return &TaskBatcher{Window: window, client: c.Clone(), pending: make(map[string][]chan taskBatchResult)}
+}
+
+// getTaskData - get the json of the task at uri from the next batched call
+func (b *TaskBatcher) getTaskData(ctx context.Context, uri string) ([]byte, error) {
+	result := make(chan taskBatchResult, 1)
+	b.mu.Lock()
+	b.pending[uri] = append(b.pending[uri], result)
+	if !b.polling {
+		b.polling = true
+		go b.poll()
+	}
+	b.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		return r.data, r.err
+	}
+}
+
+// limitWindow - keep Window no longer than wait, a check never waits longer for
+// others than the task waits between its checks
+func (b *TaskBatcher) limitWindow(wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if wait < b.Window {
+		b.Window = wait
+	}
+}
+
+// poll - after Window, get every pending task and answer its waiters
+func (b *TaskBatcher) poll() {
+	b.mu.Lock()
+	window := b.Window
+	b.mu.Unlock()
+	time.Sleep(window)
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[string][]chan taskBatchResult)
+	b.polling = false
+	b.mu.Unlock()
+
+	uris := make([]string, 0, len(pending))
+	for uri := range pending {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	size := b.Size
+	if size <= 0 {
+		size = DefaultTaskBatchSize
+	}
+
+	// calls are serialized, the client copy isn't safe to share
+	b.callmu.Lock()
+	defer b.callmu.Unlock()
+	for start := 0; start < len(uris); start += size {
+		end := start + size
+		if end > len(uris) {
+			end = len(uris)
+		}
+		found, err := b.getTasks(uris[start:end])
+		for _, uri := range uris[start:end] {
+			r := taskBatchResult{err: err}
+			if err == nil {
+				if r.data = found[uri]; r.data == nil {
+					r.err = fmt.Errorf("%w, %s", ErrTaskNotFound, uri)
+				}
+			}
+			for _, ch := range pending[uri] {
+				ch <- r
+			}
+		}
+	}
+}
+
+// getTasks - get the json of the tasks at uris in one filtered call
+func (b *TaskBatcher) getTasks(uris []string) (map[string][]byte, error) {
+	filters := make([]string, len(uris))
+	for i, uri := range uris {
+		filters[i] = fmt.Sprintf("uri='%s'", uri)
+	}
+	log.Debugf("Getting %d tasks in one call", len(uris))
+	members, err := b.client.getTaskMembers(context.Background(), []string{strings.Join(filters, " OR ")}, "")
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string][]byte, len(members))
+	for _, data := range members {
+		var t struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+		found[t.URI] = data
+	}
+	return found, nil
+}
+
+// TaskBatch - check the task through b, sharing calls with other tasks
+func TaskBatch(b *TaskBatcher) TaskOption {
+	return func(t *Task) { t.Batcher = b }
+}
+
+// WithTaskBatcher - check the power task through b, sharing calls with other power tasks
+func WithTaskBatcher(b *TaskBatcher) PowerTaskOption {
+	return func(pt *PowerTask) { pt.Batcher = b }
+}
@@ -0,0 +1,88 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// test getting tasks from the task collection
+func TestGetTasks(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/tasks/1", `{"uri":"/rest/tasks/1","taskState":"Running"}`)
+	f.HandleJSON(rest.GET, "/rest/tasks/2", `{"uri":"/rest/tasks/2","taskState":"Completed"}`)
+
+	tasks, err := c.GetTasks([]string{"uri='/rest/tasks/1' OR uri='/rest/tasks/2'"}, "")
+	assert.NoError(t, err, "GetTasks threw error -> %s", err)
+	assert.Equal(t, 2, tasks.Count)
+	assert.Equal(t, "Completed", tasks.Members[1].TaskState)
+	assert.Equal(t, c, tasks.Members[0].Client)
+	assert.Equal(t, 0, len(c.Option.Query))
+
+	// a lost session is logged in again before the call
+	c.APIKey = ""
+	_, err = c.GetTasks([]string{"uri='/rest/tasks/1'"}, "")
+	assert.NoError(t, err, "GetTasks threw error -> %s", err)
+	assert.Equal(t, "ovtest-session", c.APIKey)
+}
+
+// test concurrent waiters share task calls and each get their own task
+func TestTaskBatcher(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	const waiters = 5
+	var mu sync.Mutex
+	polls := make(map[string]int)
+	for i := 0; i < waiters; i++ {
+		uri := fmt.Sprintf("/rest/tasks/%d", i)
+		f.Handle(rest.GET, uri, func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polls[path]++
+			state := "Running"
+			if polls[path] > 2 {
+				state = "Completed"
+			}
+			return []byte(fmt.Sprintf(`{"uri":%q,"name":%q,"taskState":%q}`, path, path, state)), nil
+		})
+	}
+
+	b := c.NewTaskBatcher(20 * time.Millisecond)
+	var wg sync.WaitGroup
+	tasks := make([]*Task, waiters)
+	errs := make([]error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tasks[i], errs[i] = c.WaitForTask(fmt.Sprintf("/rest/tasks/%d", i), 10, 0, TaskBatch(b))
+		}(i)
+	}
+	wg.Wait()
+
+	collection := 0
+	for _, call := range f.Calls() {
+		assert.False(t, strings.HasPrefix(call.Path, "/rest/tasks/"), "task checks should be batched, got %s", call.Path)
+		if call.Path == "/rest/tasks" {
+			collection++
+		}
+	}
+	for i := 0; i < waiters; i++ {
+		assert.NoError(t, errs[i], "WaitForTask threw error -> %s", errs[i])
+		assert.Equal(t, fmt.Sprintf("/rest/tasks/%d", i), tasks[i].Name)
+		assert.True(t, tasks[i].TaskIsDone)
+	}
+	assert.True(t, collection < waiters*3, "expected fewer calls than checks, got %d", collection)
+
+	_, err := c.WaitForTask("/rest/tasks/missing", 10, 0, TaskBatch(b))
+	assert.True(t, errors.Is(err, ErrTaskNotFound), "expected not found, got %s", err)
+}
@@ -0,0 +1,128 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+)
+
+// taskCancelTimeout - time allowed for the cancel request of an abandoned power task
+const taskCancelTimeout = 30 * time.Second
+
+// ErrTaskNotCancellable - the appliance refused to cancel the task
+var ErrTaskNotCancellable = errors.New("Task can't be cancelled")
+
+// TaskCancelRequest - body of a task cancel request
+type TaskCancelRequest struct {
+	TaskState string `json:"taskState"` // "Cancelling"
+}
+
+// CancelTask - ask the appliance to cancel the task at uri. Only tasks the appliance
+// reports with isCancellable can be cancelled, long running user tasks such as server
+// profile applies and firmware updates; short tasks such as power changes and
+// refreshes mostly run to completion, their cancel returns ErrTaskNotCancellable.
+// The task reaches T_CANCELLED once the appliance stops it.
+func (c *OVClient) CancelTask(uri string) error {
+	// refresh login
+	c.RefreshLogin()
+	c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	return cancelTask(context.Background(), c, uri)
+}
+
+// cancelTask - put the task at uri in the Cancelling state
+func cancelTask(ctx context.Context, client TaskClient, uri string) error {
+	if uri == "" {
+		return errors.New("Unable to cancel task, no URI found")
+	}
+	log.Infof("Cancelling task %s.", uri)
+	_, err := client.RestAPICallContext(ctx, rest.PUT, uri, TaskCancelRequest{TaskState: T_CANCELLING.String()})
+	if err != nil {
+		var serr *rest.StatusError
+		if errors.As(err, &serr) {
+			switch serr.StatusCode {
+			case http.StatusNotFound:
+				return fmt.Errorf("%w, %s: %v", ErrTaskNotFound, uri, err)
+			case http.StatusBadRequest, http.StatusConflict, http.StatusMethodNotAllowed:
+				return fmt.Errorf("%w, %s: %v", ErrTaskNotCancellable, uri, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Cancel - cancel the submitted power task, see CancelTask for the tasks that can
+// be cancelled. When wait is set, check the task up to Timeout times until it ends.
+// A power task that is done, or wasn't submitted, has nothing to cancel.
+func (pt *PowerTask) Cancel(wait bool) error {
+	return pt.cancel(context.Background(), wait)
+}
+
+// cancel - cancel the power task bound to ctx
+func (pt *PowerTask) cancel(ctx context.Context, wait bool) error {
+	t := pt.getTask()
+	if t.URI == "" || t.TaskIsDone {
+		return nil
+	}
+	if err := cancelTask(ctx, t.Client, t.URI.String()); err != nil {
+		return err
+	}
+	if !wait {
+		return nil
+	}
+	for check := 0; check < t.Timeout; check++ {
+		// cancelled tasks may report task errors, the state tells when it ended
+		err := pt.GetCurrentTaskStatusContext(ctx)
+		t = pt.getTask()
+		if err != nil && len(t.TaskErrors) == 0 {
+			return err
+		}
+		if t.GetTaskState().IsTerminal() {
+			pt.setTaskIsDone()
+			log.Infof("Task %s ended %s after cancel.", t.URI, t.TaskState)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pt.GetWaitTime(check)):
+		}
+	}
+	return fmt.Errorf("%w, %s still %s after cancel", ErrTaskTimeout, t.URI, t.TaskState)
+}
+
+// abandon - cancel the power task of an executor whose context is done, the
+// appliance keeps running a task nobody waits on otherwise. Tasks the appliance
+// didn't report as cancellable are left to run. Called in its own goroutine so
+// the executor returns as soon as its context is done.
+func (pt *PowerTask) abandon() {
+	if t := pt.getTask(); t.URI == "" || t.TaskIsDone || !t.IsCancellable {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), taskCancelTimeout)
+	defer cancel()
+	if err := pt.cancel(ctx, false); err != nil {
+		log.Warnf("Unable to cancel power task %s: %s", pt.getTask().URI, err)
+	}
+}
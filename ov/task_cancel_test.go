@@ -0,0 +1,109 @@
+package ov
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCancelTask verify the cancel request and its errors
+func TestCancelTask(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.PUT, "/rest/tasks/1", `{"uri":"/rest/tasks/1","taskState":"Cancelling"}`)
+	f.HandleStatus(rest.PUT, "/rest/tasks/2", 400, "task is not cancellable")
+
+	assert.NoError(t, c.CancelTask("/rest/tasks/1"))
+	calls := f.Calls()
+	last := calls[len(calls)-1]
+	assert.Equal(t, "/rest/tasks/1", last.Path)
+	assert.Equal(t, TaskCancelRequest{TaskState: "Cancelling"}, last.Options)
+
+	err := c.CancelTask("/rest/tasks/2")
+	assert.True(t, errors.Is(err, ErrTaskNotCancellable), "expected ErrTaskNotCancellable, got %v", err)
+	err = c.CancelTask("/rest/tasks/3")
+	assert.True(t, errors.Is(err, ErrTaskNotFound), "expected ErrTaskNotFound, got %v", err)
+	assert.Error(t, c.CancelTask(""))
+	assert.True(t, T_CANCELLED.IsTerminal())
+	assert.False(t, T_CANCELLING.IsTerminal())
+}
+
+// TestPowerTaskCancel verify a running power task is cancelled and waited on
+func TestPowerTaskCancel(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 100
+	b.Cancellable = true
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	assert.NoError(t, pt.Cancel(true), "nothing submitted, nothing to cancel")
+	pt.SubmitPowerState(P_ON)
+	assert.NoError(t, pt.Cancel(true))
+	assert.Equal(t, "Cancelled", pt.TaskState)
+	assert.True(t, pt.TaskIsDone)
+	assert.Equal(t, "Off", b.GetState())
+
+	b.Cancellable = false
+	pt = pt.NewPowerTask(blade, WithWaitTime(0))
+	pt.SubmitPowerState(P_ON)
+	err = pt.Cancel(false)
+	assert.True(t, errors.Is(err, ErrTaskNotCancellable), "expected ErrTaskNotCancellable, got %v", err)
+}
+
+// TestPowerExecutorContextCancelsTask verify an abandoned power task is cancelled on the appliance
+func TestPowerExecutorContextCancelsTask(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	b := f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 1000
+	b.Cancellable = true
+	blade, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(blade, WithWaitTime(time.Millisecond), WithTimeout(100000))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = pt.PowerExecutorContext(ctx, P_ON)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %v", err)
+
+	// the cancel is sent after the executor returned
+	cancels := func() int {
+		var n int
+		for _, call := range f.Calls() {
+			if call.Method == rest.PUT && call.Path == "/rest/tasks/ovtest-1" {
+				n++
+			}
+		}
+		return n
+	}
+	for i := 0; i < 100 && cancels() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, cancels())
+	assert.Equal(t, "Off", b.GetState())
+
+	// tasks the appliance doesn't report cancellable are left to run
+	f = ovtest.NewFake()
+	c = &OVClient{f.NewRestClient()}
+	b = f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "Off")
+	b.TaskPolls = 1000
+	blade, err = c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	pt = pt.NewPowerTask(blade, WithWaitTime(time.Millisecond), WithTimeout(100000))
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = pt.PowerExecutorContext(ctx, P_ON)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %v", err)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, cancels())
+}
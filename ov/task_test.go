@@ -1,10 +1,16 @@
 package ov
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"time"
 )
 
 // test unmarshalling a json payload that has progress
@@ -45,4 +51,329 @@ func TestProgressUpdatesTaskJsonUnmarshal(t *testing.T) {
 	)
 	err := json.Unmarshal([]byte(test_json_data), &task)
 	assert.NoError(t, err, fmt.Sprintf("Failed to unmarshal task object: %s, %+v\n", err, task))
+	assert.Len(t, task.ProgressUpdatesSince(0), 2)
+	assert.Equal(t, 12566, task.ProgressUpdatesSince(1)[0].ID)
+	assert.Empty(t, task.ProgressUpdatesSince(2))
+}
+
+// test backoff policies for the wait time between task checks
+func TestTaskGetWaitTime(t *testing.T) {
+	var cases = []struct {
+		backoff BackoffPolicy
+		max     time.Duration
+		check   int
+		expects time.Duration
+	}{
+		{B_FIXED, 0, 0, 10 * time.Second},
+		{B_FIXED, 0, 5, 10 * time.Second},
+		{B_LINEAR, 0, 0, 10 * time.Second},
+		{B_LINEAR, 0, 2, 30 * time.Second},
+		{B_LINEAR, 25 * time.Second, 2, 25 * time.Second},
+		{B_EXPONENTIAL, 0, 0, 10 * time.Second},
+		{B_EXPONENTIAL, 0, 3, 80 * time.Second},
+		{B_EXPONENTIAL, time.Minute, 100, time.Minute},
+		{B_EXPONENTIAL, 0, 30, maxBackoffWaitTime},
+		{B_EXPONENTIAL, 0, 1000, maxBackoffWaitTime},
+		{B_LINEAR, 0, 1 << 40, maxBackoffWaitTime},
+	}
+	for _, c := range cases {
+		task := &Task{WaitTime: 10 * time.Second, Backoff: c.backoff, MaxWaitTime: c.max}
+		assert.Equal(t, c.expects, task.GetWaitTime(c.check), "%s backoff for check %d", c.backoff, c.check)
+	}
+}
+
+// test jitter keeps the wait time within the fraction and spreads it out
+func TestTaskGetWaitTimeJitter(t *testing.T) {
+	task := &Task{WaitTime: 10 * time.Second}
+	TaskJitter(0.2)(task)
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		wait := task.GetWaitTime(0)
+		assert.True(t, wait >= 8*time.Second && wait <= 12*time.Second, "wait %s outside of 20%% jitter", wait)
+		seen[wait] = true
+	}
+	assert.True(t, len(seen) > 1, "expected jitter to vary the wait")
+
+	task.Jitter = 5
+	for i := 0; i < 100; i++ {
+		assert.True(t, task.GetWaitTime(0) >= 0)
+	}
+
+	var pt *PowerTask
+	pt = pt.NewPowerTask(ServerHardware{}, WithWaitTime(0), WithJitter(0.5))
+	assert.Equal(t, time.Duration(0), pt.GetWaitTime(3))
+}
+
+// test the MinWaitTime floor holds after the MaxWaitTime ceiling and jitter
+func TestTaskGetWaitTimeMin(t *testing.T) {
+	task := &Task{WaitTime: time.Second, MaxWaitTime: time.Millisecond, MinWaitTime: 50 * time.Millisecond, Jitter: 1}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 50*time.Millisecond, task.GetWaitTime(i))
+	}
+	task = &Task{WaitTime: 100 * time.Millisecond, MinWaitTime: 50 * time.Millisecond, Jitter: 1}
+	for i := 0; i < 100; i++ {
+		assert.True(t, task.GetWaitTime(0) >= 50*time.Millisecond)
+	}
+}
+
+// test waiting on a task uri until it completes
+func TestWaitForTask(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	polls := 0
+	f.Handle(rest.GET, "/rest/tasks/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		polls++
+		state := "Running"
+		if polls == 3 {
+			state = "Completed"
+		}
+		return []byte(fmt.Sprintf(`{"uri":"/rest/tasks/1","name":"Create","taskState":"%s","computedPercentComplete":%d}`, state, polls*33)), nil
+	})
+
+	task, err := c.WaitForTask("/rest/tasks/1", 5, 0)
+	assert.NoError(t, err, "WaitForTask threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	assert.Equal(t, "Completed", task.TaskState)
+	assert.Equal(t, "Create", task.Name)
+	assert.Equal(t, 3, polls)
+
+	polls = 0
+	task, err = c.WaitForTask("/rest/tasks/1", 2, 0)
+	assert.True(t, errors.Is(err, ErrTaskTimeout), "expected task timeout, got %s", err)
+	assert.False(t, task.TaskIsDone)
+	assert.Equal(t, 2, polls)
+
+	_, err = c.WaitForTask("", 2, 0)
+	assert.Error(t, err)
+}
+
+// test checks of a queued task count against the pending timeout, not the timeout
+func TestWaitForTaskPendingTimeout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	polls := 0
+	f.Handle(rest.GET, "/rest/tasks/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		polls++
+		state := "Pending"
+		switch {
+		case polls == 5:
+			state = "Completed"
+		case polls > 3:
+			state = "Running"
+		}
+		return []byte(fmt.Sprintf(`{"uri":"/rest/tasks/1","name":"Create","taskState":"%s"}`, state)), nil
+	})
+
+	task, err := c.WaitForTask("/rest/tasks/1", 2, 0, TaskPendingTimeout(5))
+	assert.NoError(t, err, "WaitForTask threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	assert.Equal(t, 5, polls)
+
+	polls = 0
+	_, err = c.WaitForTask("/rest/tasks/1", 2, 0)
+	assert.True(t, errors.Is(err, ErrTaskTimeout), "pending counts in the timeout without a pending timeout, got %s", err)
+
+	polls = 0
+	_, err = c.WaitForTask("/rest/tasks/1", 10, 0, TaskPendingTimeout(2))
+	assert.True(t, errors.Is(err, ErrTaskPendingTimeout), "expected pending timeout, got %s", err)
+	assert.False(t, errors.Is(err, ErrTaskTimeout))
+	assert.Equal(t, 2, polls)
+	assert.True(t, T_NEW.IsQueued())
+	assert.False(t, T_RUNNING.IsQueued())
+}
+
+// test failed task states stop the wait with the task errors
+func TestWaitForTaskFailed(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/tasks/1", `{"uri":"/rest/tasks/1","name":"Update","taskState":"Error"}`)
+	f.HandleJSON(rest.GET, "/rest/tasks/2", `{"uri":"/rest/tasks/2","name":"Update","taskState":"Error",
+		"taskErrors":[{"message":"Firmware bundle not found.","recommendedActions":["Upload the bundle."]}]}`)
+	f.HandleJSON(rest.GET, "/rest/tasks/3", `{"uri":"/rest/tasks/3","name":"Update","taskState":"Warning"}`)
+
+	_, err := c.WaitForTask("/rest/tasks/1", 10, time.Hour)
+	assert.True(t, errors.Is(err, ErrTaskFailed), "expected task failed, got %s", err)
+	assert.Equal(t, "Task Update, /rest/tasks/1, failed with state Error", err.Error())
+
+	_, err = c.WaitForTask("/rest/tasks/2", 10, time.Hour)
+	var failed *TaskFailedError
+	assert.True(t, errors.As(err, &failed))
+	assert.Equal(t, "Error", failed.State)
+	assert.Equal(t, 1, len(failed.Errors))
+	assert.Equal(t, "Task Update, /rest/tasks/2, failed with state Error: Firmware bundle not found. Upload the bundle.", err.Error())
+
+	task, err := c.WaitForTask("/rest/tasks/3", 1, 0)
+	assert.NoError(t, err, "Warning task should be done -> %s", err)
+	assert.True(t, task.TaskIsDone)
+}
+
+// test task errors on a running task fail Wait with a TaskFailedError
+func TestTaskWaitTaskFailedError(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/tasks/1", `{"uri":"/rest/tasks/1","name":"Apply profile","taskState":"Running",
+		"taskErrors":[{"errorCode":"ProfileApplyFailed","message":"Unable to apply profile.","recommendedActions":["Retry."]}]}`)
+
+	task := (&Task{}).NewProfileTask(c)
+	task.URI = "/rest/tasks/1"
+	task.Timeout = 2
+	task.WaitTime = 0
+	err := task.Wait()
+	var failed *TaskFailedError
+	assert.True(t, errors.As(err, &failed), "expected task failed error, got %s", err)
+	assert.True(t, errors.Is(err, ErrTaskFailed))
+	assert.Equal(t, "Running", failed.State)
+	assert.Equal(t, "Task Apply profile, /rest/tasks/1, failed with state Running: Unable to apply profile. (ProfileApplyFailed) Retry.", err.Error())
+
+	err = task.GetCurrentTaskStatus()
+	assert.True(t, errors.As(err, &failed))
+}
+
+// test nested task errors are flattened into the summary
+func TestTaskErrorSummary(t *testing.T) {
+	var task Task
+	err := json.Unmarshal([]byte(`{"taskState":"Error","taskErrors":[
+		{"errorCode":"ProfileApplyFailed","message":"Unable to apply profile.","recommendedActions":["See nested errors."],
+		 "nestedErrors":[{"errorCode":"FirmwareMissing","message":"","details":"Bundle not found.","recommendedActions":["Upload the bundle.","Retry."]}]},
+		{"message":"Server is locked."}]}`), &task)
+	assert.NoError(t, err, "Unmarshal threw error -> %s", err)
+
+	errs := task.Errors()
+	assert.Equal(t, 3, len(errs))
+	assert.Equal(t, "FirmwareMissing", errs[1].ErrorCode)
+	assert.Nil(t, errs[0].NestedErrors)
+	assert.Equal(t, "Unable to apply profile. (ProfileApplyFailed) See nested errors.; "+
+		"Bundle not found. (FirmwareMissing) Upload the bundle. Retry.; Server is locked.", task.ErrorSummary())
+	assert.Equal(t, "", (&Task{}).ErrorSummary())
+}
+
+// test the task states treated as failure
+func TestTaskIsFailed(t *testing.T) {
+	task := &Task{TaskState: "Killed"}
+	assert.True(t, task.IsFailed())
+	task.TaskState = "Warning"
+	assert.False(t, task.IsFailed())
+	task.FailStates = []TaskState{T_WARNING}
+	assert.True(t, task.IsFailed())
+	task.TaskState = "Running"
+	assert.False(t, task.IsFailed())
+}
+
+// test the progress history used for soft timeouts
+func TestTaskProgress(t *testing.T) {
+	var (
+		p     TaskProgress
+		start = time.Now()
+	)
+	_, ok := p.EstimateRemaining()
+	assert.False(t, ok)
+	p.Add(10, start)
+	p.Add(10, start.Add(time.Minute))
+	assert.False(t, p.Progressing())
+	assert.True(t, p.Stalled(1))
+	assert.False(t, p.Stalled(2))
+	assert.False(t, p.Stalled(0))
+	_, ok = p.EstimateRemaining()
+	assert.False(t, ok)
+
+	p.Add(40, start.Add(2*time.Minute))
+	assert.True(t, p.Progressing())
+	assert.False(t, p.Stalled(1))
+	remaining, ok := p.EstimateRemaining()
+	assert.True(t, ok)
+	assert.Equal(t, 4*time.Minute, remaining)
+}
+
+// test a progressing task extends the timeout and a stalled task times out early
+func TestWaitForTaskSoftTimeout(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	var percents []int
+	f.Handle(rest.GET, "/rest/tasks/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		percent, state := percents[0], "Running"
+		if len(percents) > 1 {
+			percents = percents[1:]
+		}
+		if percent == 100 {
+			state = "Completed"
+		}
+		return []byte(fmt.Sprintf(`{"uri":"/rest/tasks/1","name":"Update","taskState":"%s","computedPercentComplete":%d}`, state, percent)), nil
+	})
+
+	percents = []int{10, 20, 30, 40, 50, 100}
+	task, err := c.WaitForTask("/rest/tasks/1", 3, 0)
+	assert.True(t, errors.Is(err, ErrTaskTimeout), "expected task timeout, got %s", err)
+
+	percents = []int{10, 20, 30, 40, 50, 100}
+	task, err = c.WaitForTask("/rest/tasks/1", 3, 0, TaskSoftTimeout(10))
+	assert.NoError(t, err, "WaitForTask threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+
+	percents = []int{10, 20, 20, 20, 20, 20, 20, 100}
+	_, err = c.WaitForTask("/rest/tasks/1", 20, 0, TaskSoftTimeout(30), TaskStallChecks(3))
+	assert.True(t, errors.Is(err, ErrTaskTimeout), "expected stalled task to time out, got %s", err)
+	assert.Equal(t, []int{20, 20, 100}, percents)
+}
+
+// test getting a task with its errors, resource and progress
+func TestGetTask(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.HandleJSON(rest.GET, "/rest/tasks/1", `{"uri":"/rest/tasks/1","name":"Power on","taskState":"Error",
+		"associatedResource":{"resourceName":"enc1, bay 1","resourceUri":"/rest/server-hardware/1","resourceCategory":"server-hardware"},
+		"progressUpdates":[{"statusUpdate":"Powering on.","id":1}],
+		"taskErrors":[{"errorCode":"POWER_FAILED","message":"Power on failed."}]}`)
+
+	task, err := c.GetTask("/rest/tasks/1")
+	assert.NoError(t, err, "GetTask threw error -> %s", err)
+	assert.Equal(t, "Power on", task.Name)
+	assert.Equal(t, "/rest/server-hardware/1", task.AssociatedRes.ResourceURI.String())
+	assert.Equal(t, "Powering on.", task.ProgressUpdates[0].StatusUpdate)
+	assert.Equal(t, "POWER_FAILED", task.TaskErrors[0].ErrorCode)
+	assert.Equal(t, c, task.Client)
+
+	_, err = c.GetTask("/rest/tasks/purged")
+	assert.True(t, errors.Is(err, ErrTaskNotFound), "expected task not found, got %s", err)
+	_, err = c.GetTask("")
+	assert.Error(t, err)
+}
+
+// test task states parse and match like power states
+func TestParseTaskState(t *testing.T) {
+	all := []TaskState{T_COMPLETED, T_ERROR, T_INTERRUPTED, T_KILLED, T_NEW, T_PENDING, T_RUNNING,
+		T_STARTING, T_STOPPING, T_SUSPENDED, T_TERMINATED, T_UNKNOWN, T_WARNING}
+	for _, ts := range all {
+		state, err := ParseTaskState(ts.String())
+		assert.NoError(t, err)
+		assert.Equal(t, ts, state)
+		assert.True(t, ts.Equal(" "+strings.ToLower(ts.String())+" "))
+	}
+	assert.Equal(t, T_INTERRUPTED, T_INERRUPTED)
+
+	state, err := ParseTaskState("Bogus")
+	assert.True(t, errors.Is(err, ErrUnknownTaskState), "expected ErrUnknownTaskState, got %s", err)
+	assert.Equal(t, T_UNKNOWN, state)
+	assert.False(t, TaskState(0).Equal("Completed"))
+
+	var terminal []TaskState
+	for _, ts := range all {
+		if ts.IsTerminal() {
+			terminal = append(terminal, ts)
+		}
+	}
+	assert.Equal(t, []TaskState{T_COMPLETED, T_ERROR, T_INTERRUPTED, T_KILLED, T_TERMINATED, T_WARNING}, terminal)
+	assert.Equal(t, T_RUNNING, (&Task{TaskState: "Running"}).GetTaskState())
+}
+
+// test a task answered with an html page fails with the start of the page
+func TestTaskNonJSONResponse(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.Handle(rest.GET, "/rest/tasks/1", func(ctx context.Context, method rest.Method, path string, options interface{}) ([]byte, error) {
+		return []byte("<html><body>Service Unavailable</body></html>"), nil
+	})
+	task := &Task{Client: c, URI: "/rest/tasks/1"}
+	err := task.GetCurrentTaskStatus()
+	assert.True(t, errors.Is(err, rest.ErrNonJSONResponse), "expected non json response, got %s", err)
+	assert.Equal(t, "Error getting task /rest/tasks/1: Unexpected non-JSON response: <html><body>Service Unavailable</body></html>", err.Error())
 }
@@ -0,0 +1,125 @@
+/*
+(c) Copyright [2015] Hewlett Packard Enterprise Development LP
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ov -
+package ov
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// UIDState - state of the blade UID, locator, LED
+type UIDState int
+
+const (
+	UID_ON UIDState = 1 + iota
+	UID_OFF
+	UID_BLINKING
+)
+
+var uidstates = [...]string{
+	"On",
+	"Off",
+	"Blinking",
+}
+
+func (u UIDState) String() string {
+	if u < 1 || int(u) > len(uidstates) {
+		return ""
+	}
+	return uidstates[u-1]
+}
+
+// Equal - case insensitive match of s, ignoring surrounding whitespace
+func (u UIDState) Equal(s string) bool {
+	return u.String() != "" && strings.EqualFold(strings.TrimSpace(s), u.String())
+}
+
+// ErrUnknownUIDState - the blade reported a uid state that isn't recognized
+var ErrUnknownUIDState = errors.New("Un-known uid state")
+
+// ParseUIDState - get the UIDState for a uid state reported by the appliance
+func ParseUIDState(s string) (UIDState, error) {
+	for i := range uidstates {
+		if u := UIDState(i + 1); u.Equal(s) {
+			return u, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownUIDState, s)
+}
+
+// PatchRequest - a json patch operation on a resource
+type PatchRequest struct {
+	Op    string      `json:"op"`    // "op": "replace",
+	Path  string      `json:"path"`  // "path": "/uidState",
+	Value interface{} `json:"value"` // "value": "On"
+}
+
+// GetUIDState - get the uid state the server hardware reported
+func (h ServerHardware) GetUIDState() (UIDState, error) {
+	return ParseUIDState(h.UIDState)
+}
+
+// SetUIDState - turn the uid light of the server hardware at uri on, off or
+// blinking and wait for the task, nothing is sent when it is already in state s
+// and the task returned is done
+func (c *OVClient) SetUIDState(uri utils.Nstring, s UIDState, opts ...TaskOption) (*Task, error) {
+	if s.String() == "" {
+		return nil, fmt.Errorf("%w %d", ErrUnknownUIDState, int(s))
+	}
+	hardware, err := c.GetServerHardware(uri)
+	if err != nil {
+		return nil, err
+	}
+	if hardware.URI.IsNil() {
+		return nil, ErrNoBladeHardware
+	}
+	if s.Equal(hardware.UIDState) {
+		log.Infof("Desired uid state already set -> %s", s)
+		return &Task{Client: c, TaskIsDone: true}, nil
+	}
+
+	log.Infof("Setting uid %s for server %s, %s.", s, hardware.Name, hardware.SerialNumber)
+	body := []PatchRequest{{Op: "replace", Path: "/uidState", Value: s.String()}}
+	return c.patchResource(hardware.URI, body, "uid state", opts...)
+}
+
+// patchResource - send the json patch ops for the resource at uri and wait for
+// the task, what names the request in errors and logs
+func (c *OVClient) patchResource(uri utils.Nstring, body []PatchRequest, what string, opts ...TaskOption) (*Task, error) {
+	header := c.GetAuthHeaderMap()
+	header["Content-Type"] = "application/json-patch+json"
+	c.SetAuthHeaderOptions(header)
+	// calls that don't set their own headers must not send a json patch
+	defer c.SetAuthHeaderOptions(c.GetAuthHeaderMap())
+	data, err := c.RestAPICall(rest.PATCH, uri.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("Error with %s request: %w", what, err)
+	}
+	log.Debugf("patch %s %s", what, data)
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, err
+	}
+	timeout, wait := powerTaskDefaults()
+	return c.WaitForTask(task.URI.String(), timeout, wait, opts...)
+}
@@ -0,0 +1,61 @@
+package ov
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/ov/ovtest"
+	"github.com/HewlettPackard/oneview-golang/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+// uid state test, the light is patched and the task waited on
+func TestSetUIDState(t *testing.T) {
+	f := ovtest.NewFake()
+	c := &OVClient{f.NewRestClient()}
+	f.AddBlade("/rest/server-hardware/1", "enc1, bay 1", "On")
+
+	task, err := c.SetUIDState("/rest/server-hardware/1", UID_BLINKING, TaskWaitTime(0))
+	assert.NoError(t, err, "SetUIDState threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	hw, err := c.GetServerHardware("/rest/server-hardware/1")
+	assert.NoError(t, err, "GetServerHardware threw error -> %s", err)
+	uid, err := hw.GetUIDState()
+	assert.NoError(t, err, "GetUIDState threw error -> %s", err)
+	assert.Equal(t, UID_BLINKING, uid)
+
+	// already blinking, nothing is sent
+	before := len(f.Calls())
+	task, err = c.SetUIDState("/rest/server-hardware/1", UID_BLINKING)
+	assert.NoError(t, err, "SetUIDState threw error -> %s", err)
+	assert.True(t, task.TaskIsDone)
+	for _, call := range f.Calls()[before:] {
+		assert.NotEqual(t, rest.PATCH, call.Method)
+	}
+
+	_, err = c.SetUIDState("/rest/server-hardware/1", UIDState(9))
+	assert.True(t, errors.Is(err, ErrUnknownUIDState))
+
+	// without options the patch task is checked with the power defaults
+	SetDefaultPowerTimeout(3)
+	SetDefaultPowerWaitTime(0)
+	defer SetDefaultPowerTimeout(0)
+	defer SetDefaultPowerWaitTime(-1)
+	task, err = c.SetUIDState("/rest/server-hardware/1", UID_OFF)
+	assert.NoError(t, err, "SetUIDState threw error -> %s", err)
+	assert.Equal(t, 3, task.Timeout)
+	assert.Equal(t, time.Duration(0), task.WaitTime)
+}
+
+// uid state string and parse test
+func TestParseUIDState(t *testing.T) {
+	for _, u := range []UIDState{UID_ON, UID_OFF, UID_BLINKING} {
+		p, err := ParseUIDState(" " + u.String() + " ")
+		assert.NoError(t, err, "ParseUIDState threw error -> %s", err)
+		assert.Equal(t, u, p)
+	}
+	assert.True(t, UID_BLINKING.Equal("blinking"))
+	_, err := ParseUIDState("Flashing")
+	assert.True(t, errors.Is(err, ErrUnknownUIDState))
+}
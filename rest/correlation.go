@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// CorrelationHeader - header carrying the correlation id of a call, so client
+// logs can be matched with the appliance or a proxy in front of it
+const CorrelationHeader = "X-Correlation-Id"
+
+type correlationKey struct{}
+
+// WithCorrelationID - returns a copy of ctx carrying id, rest calls made with it
+// send id in the CorrelationHeader and log it
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID - the correlation id carried by ctx, empty when there is none
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// NewCorrelationID - a random correlation id
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// CorrelationContext - returns ctx carrying a correlation id, the one already
+// on ctx, else the Client CorrelationID, else a new random one, use it to give
+// the calls of an operation one id
+func (c *Client) CorrelationContext(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	id := c.CorrelationID
+	if id == "" {
+		id = NewCorrelationID()
+	}
+	return WithCorrelationID(ctx, id)
+}
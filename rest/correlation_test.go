@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// correlationTransport - records the correlation id of every call, failing the first fail ones
+type correlationTransport struct {
+	ids  []string
+	fail int
+}
+
+func (f *correlationTransport) RestAPICall(method Method, path string, options interface{}) ([]byte, error) {
+	return f.RestAPICallContext(context.Background(), method, path, options)
+}
+
+func (f *correlationTransport) RestAPICallContext(ctx context.Context, method Method, path string, options interface{}) ([]byte, error) {
+	f.ids = append(f.ids, CorrelationID(ctx))
+	if len(f.ids) <= f.fail {
+		return nil, &StatusError{StatusCode: 500, Status: "500 Internal Server Error"}
+	}
+	return []byte("{}"), nil
+}
+
+// TestCorrelationID - retries share one id, the context id wins over the Client one,
+// a random id is made per call when there is none
+func TestCorrelationID(t *testing.T) {
+	f := &correlationTransport{fail: 1}
+	c := &Client{Transport: f, RetryCount: 1, RetryBackoff: time.Millisecond}
+	_, err := c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err)
+	c.RestAPICall(GET, "/rest/version", nil)
+	assert.Len(t, f.ids, 3)
+	assert.Len(t, f.ids[0], 16)
+	assert.Equal(t, f.ids[0], f.ids[1], "retries carry the same id")
+	assert.NotEqual(t, f.ids[0], f.ids[2], "each call gets its own id")
+
+	f = &correlationTransport{}
+	c = &Client{Transport: f, CorrelationID: "client-id"}
+	c.RestAPICall(GET, "/rest/version", nil)
+	c.RestAPICallContext(WithCorrelationID(context.Background(), "ctx-id"), GET, "/rest/version", nil)
+	assert.Equal(t, []string{"client-id", "ctx-id"}, f.ids)
+}
+
+// TestCorrelationHeader - the id is sent in the CorrelationHeader over http
+func TestCorrelationHeader(t *testing.T) {
+	var seen []string
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		seen = append(seen, r.Header.Get(CorrelationHeader))
+		return &http.Response{
+			StatusCode: 200,
+			Status:     "200 OK",
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			Header:     make(http.Header),
+			Request:    r,
+		}, nil
+	})
+	var c *Client
+	c = c.NewClient("user", "key", "https://appliance.invalid", WithRoundTripper(rt))
+	_, err := c.RestAPICallContext(WithCorrelationID(context.Background(), "op-1"), GET, "/rest/version", nil)
+	assert.NoError(t, err)
+	c.RestAPICall(GET, "/rest/version", nil)
+	assert.Len(t, seen, 2)
+	assert.Equal(t, "op-1", seen[0])
+	assert.NotEmpty(t, seen[1])
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrNonJSONResponse - the appliance, or a proxy in front of it, answered with a body
+// that isn't json, an html error page for example
+var ErrNonJSONResponse = errors.New("Unexpected non-JSON response")
+
+// snippetLength - most characters of a non-json body quoted in errors
+const snippetLength = 120
+
+// CheckJSON - nil when data looks like a json object or array, an error wrapping
+// ErrNonJSONResponse with the start of the body otherwise
+func CheckJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrNonJSONResponse, snippet(trimmed))
+}
+
+// checkHTMLResponse - an error wrapping ErrNonJSONResponse for an html page, told
+// by its content type or its first character, other bodies are left to the caller
+func checkHTMLResponse(contentType string, data []byte) error {
+	media, _, _ := mime.ParseMediaType(contentType)
+	trimmed := bytes.TrimSpace(data)
+	if media == "text/html" || (len(trimmed) > 0 && trimmed[0] == '<') {
+		return fmt.Errorf("%w, content type %q: %s", ErrNonJSONResponse, contentType, snippet(trimmed))
+	}
+	return nil
+}
+
+// snippet - the start of a body on a single line, for error messages
+func snippet(data []byte) string {
+	s := strings.Join(strings.Fields(string(data)), " ")
+	if len(s) > snippetLength {
+		s = s[:snippetLength] + "..."
+	}
+	if s == "" {
+		return "empty body"
+	}
+	return s
+}
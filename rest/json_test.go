@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheckJSON verify bodies that aren't json are refused with the start of the body
+func TestCheckJSON(t *testing.T) {
+	assert.NoError(t, CheckJSON([]byte(` {"uri":"/rest/tasks/1"}`)))
+	assert.NoError(t, CheckJSON([]byte(`[]`)))
+	err := CheckJSON([]byte("<html>\n  <body>502 Bad Gateway</body>\n</html>"))
+	assert.True(t, errors.Is(err, ErrNonJSONResponse))
+	assert.Equal(t, "Unexpected non-JSON response: <html> <body>502 Bad Gateway</body> </html>", err.Error())
+	assert.Equal(t, "Unexpected non-JSON response: empty body", CheckJSON(nil).Error())
+	err = CheckJSON([]byte(strings.Repeat("x", 200)))
+	assert.Equal(t, "Unexpected non-JSON response: "+strings.Repeat("x", snippetLength)+"...", err.Error())
+}
+
+// TestClientHTMLResponse verify an html page from a proxy is reported, with an ok or
+// an error status
+func TestClientHTMLResponse(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		w.Write([]byte("<html><body>Proxy login</body></html>"))
+	}))
+	defer ts.Close()
+
+	var c *Client
+	c = c.NewClient("user", "key", ts.URL)
+	_, err := c.RestAPICall(GET, "/rest/tasks/1", nil)
+	assert.True(t, errors.Is(err, ErrNonJSONResponse), "expected non json response, got %s", err)
+	assert.Contains(t, err.Error(), `content type "text/html; charset=utf-8": <html><body>Proxy login</body></html>`)
+
+	status = http.StatusBadGateway
+	_, err = c.RestAPICall(GET, "/rest/tasks/1", nil)
+	var serr *StatusError
+	assert.True(t, errors.As(err, &serr))
+	assert.Equal(t, http.StatusBadGateway, serr.StatusCode)
+	assert.Contains(t, serr.Details, "Proxy login")
+}
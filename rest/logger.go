@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// Logger - logging interface used by the package, replace it with SetLogger
+// to route logs into an existing logging library
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// current - the Logger set with SetLogger, in a loggerBox as every value an
+// atomic.Value holds must have the same type
+var current atomic.Value
+
+type loggerBox struct{ Logger }
+
+func init() {
+	current.Store(loggerBox{NewStdLogger(os.Getenv("ONEVIEW_DEBUG") == "true")})
+}
+
+// log is the Logger used by the package
+var log Logger = sharedLogger{}
+
+// SetLogger - set the Logger used by the package, nil discards all logs, safe
+// to call while other goroutines are logging
+func SetLogger(l Logger) {
+	if l == nil {
+		l = noopLogger{}
+	}
+	if _, ok := l.(sharedLogger); ok {
+		// it already forwards to the current Logger, it can't replace it
+		return
+	}
+	current.Store(loggerBox{l})
+}
+
+// GetLogger - get the Logger used by the package
+func GetLogger() Logger {
+	return current.Load().(loggerBox).Logger
+}
+
+// SharedLogger - get a Logger sending each message to the Logger set with SetLogger
+// at the time it is logged, packages built on rest log with it so one SetLogger
+// reaches all of them
+func SharedLogger() Logger {
+	return sharedLogger{}
+}
+
+// sharedLogger - Logger forwarding to GetLogger
+type sharedLogger struct{}
+
+func (sharedLogger) Debugf(format string, args ...interface{}) { GetLogger().Debugf(format, args...) }
+func (sharedLogger) Infof(format string, args ...interface{})  { GetLogger().Infof(format, args...) }
+func (sharedLogger) Warnf(format string, args ...interface{})  { GetLogger().Warnf(format, args...) }
+func (sharedLogger) Errorf(format string, args ...interface{}) { GetLogger().Errorf(format, args...) }
+
+// StdLogger - default Logger, writes info and warnings to Out, errors and debug to Err
+type StdLogger struct {
+	Out   io.Writer
+	Err   io.Writer
+	Debug bool // when false debug messages are dropped
+}
+
+// NewStdLogger - get a new StdLogger writing to stdout and stderr
+func NewStdLogger(debug bool) *StdLogger {
+	return &StdLogger{Out: os.Stdout, Err: os.Stderr, Debug: debug}
+}
+
+// Debugf - log a debug message
+func (l *StdLogger) Debugf(format string, args ...interface{}) {
+	if l.Debug {
+		fmt.Fprintf(l.Err, format+"\n", args...)
+	}
+}
+
+// Infof - log an info message
+func (l *StdLogger) Infof(format string, args ...interface{}) {
+	fmt.Fprintf(l.Out, format+"\n", args...)
+}
+
+// Warnf - log a warning message
+func (l *StdLogger) Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(l.Out, format+"\n", args...)
+}
+
+// Errorf - log an error message
+func (l *StdLogger) Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(l.Err, format+"\n", args...)
+}
+
+// noopLogger - Logger that discards all messages
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Infof(format string, args ...interface{})  {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
+func (noopLogger) Errorf(format string, args ...interface{}) {}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Fields - structured keys added to log lines by a FieldLogger
+type Fields map[string]interface{}
+
+// FieldLogger - Logger that can add structured keys to the lines it writes, the
+// packages use WithFields when the Logger set supports it
+type FieldLogger interface {
+	Logger
+	WithFields(fields Fields) Logger
+}
+
+// JSONLogger - Logger writing one json object per line to Out, with the time,
+// level, message and any fields given with WithFields
+type JSONLogger struct {
+	Out    io.Writer
+	Debug  bool // when false debug messages are dropped
+	fields Fields
+	mu     *sync.Mutex
+}
+
+// NewJSONLogger - get a new JSONLogger writing to w, debug messages are written
+// when ONEVIEW_DEBUG is true
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{Out: w, Debug: os.Getenv("ONEVIEW_DEBUG") == "true", mu: &sync.Mutex{}}
+}
+
+// WithFields - a JSONLogger adding fields to every line, sharing the writer of l
+func (l *JSONLogger) WithFields(fields Fields) Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	child := *l
+	child.fields = merged
+	return &child
+}
+
+// Debugf - log a debug message
+func (l *JSONLogger) Debugf(format string, args ...interface{}) {
+	if l.Debug {
+		l.write("debug", format, args)
+	}
+}
+
+// Infof - log an info message
+func (l *JSONLogger) Infof(format string, args ...interface{}) { l.write("info", format, args) }
+
+// Warnf - log a warning message
+func (l *JSONLogger) Warnf(format string, args ...interface{}) { l.write("warn", format, args) }
+
+// Errorf - log an error message
+func (l *JSONLogger) Errorf(format string, args ...interface{}) { l.write("error", format, args) }
+
+// write - write a line at level, a field that doesn't marshal is written as text
+func (l *JSONLogger) write(level, format string, args []interface{}) {
+	line := make(map[string]interface{}, len(l.fields)+3)
+	for k, v := range l.fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		} else if _, err := json.Marshal(v); err != nil {
+			v = fmt.Sprint(v)
+		}
+		line[k] = v
+	}
+	line["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	line["level"] = level
+	line["msg"] = fmt.Sprintf(format, args...)
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	if l.mu != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+	}
+	l.Out.Write(append(data, '\n'))
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJSONLogger verify each message is a json line with its level and fields
+func TestJSONLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := NewJSONLogger(&out)
+	l.Debug = false
+	l.Debugf("dropped")
+	l.Infof("info %d", 1)
+	l.WithFields(Fields{"serial": "SN1", "percent": 50, "err": errors.New("boom")}).Warnf("warn %d", 2)
+	l.Errorf("error %d", 3)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		assert.NotEmpty(t, entry["time"])
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "info 1", entries[0]["msg"])
+	assert.Equal(t, "warn", entries[1]["level"])
+	assert.Equal(t, "SN1", entries[1]["serial"])
+	assert.Equal(t, 50.0, entries[1]["percent"])
+	assert.Equal(t, "boom", entries[1]["err"])
+	assert.Equal(t, "error", entries[2]["level"])
+	assert.Nil(t, entries[2]["serial"], "fields only apply to the derived logger")
+
+	var _ FieldLogger = l
+}
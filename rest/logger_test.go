@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStdLogger verify messages are written to the right writer
+func TestStdLogger(t *testing.T) {
+	var out, err bytes.Buffer
+	l := &StdLogger{Out: &out, Err: &err}
+	l.Debugf("debug %d", 1)
+	l.Infof("info %d", 2)
+	l.Warnf("warn %d", 3)
+	l.Errorf("error %d", 4)
+	assert.Equal(t, "info 2\nwarn 3\n", out.String())
+	assert.Equal(t, "error 4\n", err.String())
+
+	l.Debug = true
+	l.Debugf("debug %d", 5)
+	assert.Equal(t, "error 4\ndebug 5\n", err.String())
+}
+
+// TestSetLogger verify the package logger can be replaced
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	var out bytes.Buffer
+	SetLogger(&StdLogger{Out: &out, Err: &out})
+	log.Infof("hello %s", "world")
+	assert.Equal(t, "hello world\n", out.String())
+
+	SetLogger(nil)
+	log.Infof("dropped")
+	assert.Equal(t, "hello world\n", out.String())
+}
+
+// TestSetLoggerConcurrent verify the logger can be replaced while other goroutines
+// log, and the shared logger follows it, run with -race
+func TestSetLoggerConcurrent(t *testing.T) {
+	defer SetLogger(GetLogger())
+	shared := SharedLogger()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				shared.Debugf("call %d", j)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		SetLogger(noopLogger{})
+	}
+	wg.Wait()
+
+	var out bytes.Buffer
+	SetLogger(&StdLogger{Out: &out, Err: &out})
+	shared.Infof("after %s", "set")
+	SetLogger(shared)
+	shared.Infof("still")
+	assert.Equal(t, "after set\nstill\n", out.String(), "the shared logger can't replace the one it forwards to")
+}
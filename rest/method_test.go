@@ -13,4 +13,5 @@ func TestMethod(t *testing.T) {
 	assert.Equal(t, "POST", POST.String(), "POST should be string")
 	assert.Equal(t, "PUT", PUT.String(), "PUT should be string")
 	assert.Equal(t, "DELETE", DELETE.String(), "DELETE should be string")
+	assert.Equal(t, "PATCH", PATCH.String(), "PATCH should be string")
 }
@@ -2,16 +2,19 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"reflect"
+	"time"
 
 	"github.com/HewlettPackard/oneview-golang/utils"
-	"github.com/docker/machine/libmachine/log"
 )
 
 // Options for REST call
@@ -20,7 +23,9 @@ type Options struct {
 	Query   map[string]interface{}
 }
 
-// Client - generic REST api client
+// Client - generic REST api client. A Client is not safe for concurrent use, calls
+// set the headers and query string on it for the next call and a session refresh
+// replaces its APIKey, give each goroutine its own Clone
 type Client struct {
 	Method
 	User       string
@@ -31,12 +36,129 @@ type Client struct {
 	SSLVerify  bool
 	Endpoint   string
 	Option     Options
+	// RetryCount - number of retries for GETs that hit a 5xx or connection
+	// error, 0 makes a single attempt
+	RetryCount int
+	// RetryBackoff - wait before the first retry, doubled on each retry
+	RetryBackoff time.Duration
+	// MaxRetryBackoff - ceiling the doubled RetryBackoff stops at,
+	// DefaultMaxRetryBackoff when not set
+	MaxRetryBackoff time.Duration
+	// Transport - when set, rest calls are handed to it instead of going
+	// over http with the query string on the path, use it to inject a fake
+	// appliance in tests
+	Transport RestClient
+	// HTTPClient - when set, used for every request as is, so its TLS, proxy
+	// and timeout settings apply instead of SSLVerify and RootCAs, see WithHTTPClient
+	HTTPClient *http.Client
+	// RootCAs - certificates trusted when SSLVerify is on, the system pool when nil
+	RootCAs *x509.CertPool
+	// DisableSessionRefresh - don't login again with User and Password and retry
+	// when a call answers 401 because the session APIKey expired
+	DisableSessionRefresh bool
+	// LogBodies - debug log request and response bodies, redacted, see RedactedFields
+	LogBodies bool
+	// MaxIdleConns, MaxIdleConnsPerHost, IdleConnTimeout - idle connections kept
+	// alive for reuse by the default http client, see WithConnPool
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// MaxBodySize - most bytes read from a response body, larger ones fail with
+	// ErrBodyTooLarge, 0 reads bodies whole
+	MaxBodySize int64
+	// CorrelationID - sent in the CorrelationHeader of calls whose context
+	// carries none, see WithCorrelationID, a random one per call when empty
+	CorrelationID string
+
+	limiter *rateLimiter
+	pool    *connPool
+}
+
+// SetSSLVerify - verify the appliance certificate, turn it off for lab appliances
+// with self-signed certificates
+func (c *Client) SetSSLVerify(verify bool) {
+	c.SSLVerify = verify
+}
+
+// SetCACert - trust the PEM encoded certificates in pem, turns on SSLVerify
+func (c *Client) SetCACert(pem []byte) error {
+	if c.RootCAs == nil {
+		c.RootCAs = x509.NewCertPool()
+	}
+	if !c.RootCAs.AppendCertsFromPEM(pem) {
+		return errors.New("Error with CA certificate, no PEM certificates found")
+	}
+	c.SSLVerify = true
+	return nil
+}
+
+// TLSConfig - the tls config used for requests made with the default http client
+func (c *Client) TLSConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: !c.SSLVerify, RootCAs: c.RootCAs}
+}
+
+// ClientOption - option for configuring a new Client
+type ClientOption func(*Client)
+
+// WithHTTPClient - send requests with hc instead of the default client
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithRoundTripper - send requests with a client using rt as its transport
+func WithRoundTripper(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = &http.Client{Transport: rt}
+	}
+}
+
+// ApplyOptions - apply options to the client
+func (c *Client) ApplyOptions(opts ...ClientOption) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// RestClient - makes rest calls, Client satisfies it
+type RestClient interface {
+	RestAPICall(method Method, path string, options interface{}) ([]byte, error)
 }
 
-// NewClient - get a new network client
-func (c *Client) NewClient(user, key, endpoint string) *Client {
+// ContextRestClient - a RestClient that can also bound calls with a context
+type ContextRestClient interface {
+	RestClient
+	RestAPICallContext(ctx context.Context, method Method, path string, options interface{}) ([]byte, error)
+}
+
+// NewClient - get a new network client, the appliance certificate is verified by default
+func (c *Client) NewClient(user, key, endpoint string, opts ...ClientOption) *Client {
 	var options Options
-	return &Client{User: user, APIKey: key, Endpoint: endpoint, Option: options}
+	c = &Client{User: user, APIKey: key, Endpoint: endpoint, Option: options, SSLVerify: true}
+	c.ApplyOptions(opts...)
+	return c
+}
+
+// Clone - copy of the client for another goroutine, with its own headers and query
+// string, clones share the rate limit, the HTTPClient and the Transport
+func (c *Client) Clone() *Client {
+	poolMu.Lock()
+	clone := *c
+	poolMu.Unlock()
+	if c.Option.Headers != nil {
+		clone.Option.Headers = make(map[string]string, len(c.Option.Headers))
+		for k, v := range c.Option.Headers {
+			clone.Option.Headers[k] = v
+		}
+	}
+	if c.Option.Query != nil {
+		clone.Option.Query = make(map[string]interface{}, len(c.Option.Query))
+		for k, v := range c.Option.Query {
+			clone.Option.Query[k] = v
+		}
+	}
+	return &clone
 }
 
 // isOkStatus - check the return status of the response
@@ -87,10 +209,103 @@ func (c *Client) SetAuthHeaderOptions(headers map[string]string) {
 	c.Option.Headers = headers
 }
 
-// RestAPICall - general rest method caller
+// RestAPICall - general rest method caller, GETs are retried up to RetryCount times
 func (c *Client) RestAPICall(method Method, path string, options interface{}) ([]byte, error) {
-	log.Debugf("RestAPICall %s - %s%s", method, utils.Sanatize(c.Endpoint), path)
+	return c.RestAPICallContext(context.Background(), method, path, options)
+}
+
+// RestAPICallContext - rest method caller bound to ctx, the request and any
+// retry waits are abandoned when ctx is cancelled or its deadline passes,
+// every attempt carries the same correlation id, see WithCorrelationID
+func (c *Client) RestAPICallContext(ctx context.Context, method Method, path string, options interface{}) ([]byte, error) {
+	var data []byte
+	err := c.retry(ctx, method, path, func(ctx context.Context) (err error) {
+		data, err = c.restAPICall(ctx, method, path, options)
+		return err
+	})
+	return data, err
+}
+
+// retry - make call until it succeeds, fails with an error that isn't retryable
+// for method or RetryCount retries are done, every attempt carries one correlation id
+func (c *Client) retry(ctx context.Context, method Method, path string, call func(ctx context.Context) error) error {
+	ctx = c.CorrelationContext(ctx)
+	err := call(ctx)
+	for attempt := 0; attempt < c.RetryCount && ctx.Err() == nil && isRetryable(method, err); attempt++ {
+		wait := c.getRetryWait(attempt, err)
+		log.Warnf("[%s] Retrying %s %s in %s, attempt %d of %d: %s", CorrelationID(ctx), method, path, wait, attempt+1, c.RetryCount, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		err = call(ctx)
+	}
+	return err
+}
 
+// restAPICall - make a single rest call
+func (c *Client) restAPICall(ctx context.Context, method Method, path string, options interface{}) ([]byte, error) {
+	log.Debugf("[%s] RestAPICall %s - %s%s", CorrelationID(ctx), method, utils.Sanatize(c.Endpoint), path)
+	if c.LogBodies {
+		c.logRequest(ctx, method, path, options)
+	}
+	start := time.Now()
+	data, err := c.send(ctx, method, path, options)
+	observeRestCall(method, start, err)
+	if c.LogBodies {
+		c.logResponse(ctx, method, path, data, err)
+	}
+	return data, err
+}
+
+// send - send a single rest call to the Transport or over http
+func (c *Client) send(ctx context.Context, method Method, path string, options interface{}) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.Transport != nil {
+		return c.sendTransport(ctx, method, path, options)
+	}
+	resp, err := c.do(ctx, method, path, options)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(c.limitBody(resp.Body))
+	if errors.Is(err, ErrBodyTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, &transportError{err}
+	}
+	if err := checkHTMLResponse(resp.Header.Get("Content-Type"), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// sendTransport - hand a single rest call to the Transport, with the query
+// string on the path as the appliance would get it
+func (c *Client) sendTransport(ctx context.Context, method Method, path string, options interface{}) ([]byte, error) {
+	if len(c.Option.Query) > 0 {
+		var u url.URL
+		c.GetQueryString(&u)
+		path += "?" + u.RawQuery
+	}
+	if t, ok := c.Transport.(ContextRestClient); ok {
+		return t.RestAPICallContext(ctx, method, path, options)
+	}
+	return c.Transport.RestAPICall(method, path, options)
+}
+
+// do - send a single rest call over http, a response with an error status is
+// turned into a StatusError, the caller closes the body of the one returned
+func (c *Client) do(ctx context.Context, method Method, path string, options interface{}) (*http.Response, error) {
 	var (
 		Url *url.URL
 		err error
@@ -106,16 +321,11 @@ func (c *Client) RestAPICall(method Method, path string, options interface{}) ([
 	// Manage the query string
 	c.GetQueryString(Url)
 
-	// TODO: this should have a real cert
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
+	// get a client, an injected client is used as is, the default one is pooled
+	client := c.getHTTPClient()
 
-	// get a client
-	client := &http.Client{Transport: tr}
-
-	log.Debugf("*** url => %s", Url.String())
-	log.Debugf("*** method => %s", method.String())
+	log.Debugf("[%s] *** url => %s", CorrelationID(ctx), Url.String())
+	log.Debugf("[%s] *** method => %s", CorrelationID(ctx), method.String())
 
 	// parse url
 	reqUrl, err := url.Parse(Url.String())
@@ -129,64 +339,52 @@ func (c *Client) RestAPICall(method Method, path string, options interface{}) ([
 		if err != nil {
 			return nil, err
 		}
-		log.Debugf("*** options => %+v", bytes.NewBuffer(OptionsJSON))
-		req, err = http.NewRequest(method.String(), reqUrl.String(), bytes.NewBuffer(OptionsJSON))
+		req, err = http.NewRequestWithContext(ctx, method.String(), reqUrl.String(), bytes.NewBuffer(OptionsJSON))
 	} else {
-		req, err = http.NewRequest(method.String(), reqUrl.String(), nil)
+		req, err = http.NewRequestWithContext(ctx, method.String(), reqUrl.String(), nil)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("Error with request: %v - %q", Url, err)
 	}
 
-	// setup proxy
-	proxyUrl, err := http.ProxyFromEnvironment(req)
-	if err != nil {
-		return nil, fmt.Errorf("Error with proxy: %v - %q", proxyUrl, err)
-	}
-	if proxyUrl != nil {
-		tr.Proxy = http.ProxyURL(proxyUrl)
-		log.Debugf("*** proxy => %+v", tr.Proxy)
-	}
-
 	// build the auth headerU
 	for k, v := range c.Option.Headers {
-		log.Debugf("Headers -> %s -> %+v\n", k, v)
 		req.Header.Add(k, v)
 	}
+	if id := CorrelationID(ctx); id != "" {
+		req.Header.Set(CorrelationHeader, id)
+	}
 
 	// req.SetBasicAuth(c.User, c.APIKey)
 	req.Method = fmt.Sprintf("%s", method.String())
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, &transportError{err}
 	}
-	defer resp.Body.Close()
-
-	// TODO: CLeanup Later
-	// DEBUGGING WHILE WE WORK
-	// DEBUGGING WHILE WE WORK
-	// fmt.Printf("METHOD --> %+v\n",method)
-	log.Debugf("REQ    --> %+v\n", req)
-	log.Debugf("RESP   --> %+v\n", resp)
-	log.Debugf("ERROR  --> %+v\n", err)
-	// DEBUGGING WHILE WE WORK
 
-	data, err := ioutil.ReadAll(resp.Body)
+	log.Debugf("[%s] Headers -> %+v", CorrelationID(ctx), RedactHeaders(c.Option.Headers))
+	log.Debugf("[%s] Response status -> %s", CorrelationID(ctx), resp.Status)
 
 	if !c.isOkStatus(resp.StatusCode) {
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(c.limitBody(resp.Body))
 		type apiErr struct {
 			Err string `json:"details"`
 		}
 		var outErr apiErr
 		json.Unmarshal(data, &outErr)
-		return nil, fmt.Errorf("Error in response: %s\n Response Status: %s", outErr.Err, resp.Status)
-	}
-
-	if err != nil {
-		return nil, err
+		// an html error page from a proxy has no details, quote it instead
+		if herr := checkHTMLResponse(resp.Header.Get("Content-Type"), data); outErr.Err == "" && herr != nil {
+			outErr.Err = herr.Error()
+		}
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Details:    outErr.Err,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
-
-	return data, nil
+	return resp, nil
 }
@@ -1 +1,155 @@
 package rest
+
+import (
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTransport - answers rest calls from memory
+type fakeTransport struct {
+	calls int
+	fail  int
+}
+
+func (f *fakeTransport) RestAPICall(method Method, path string, options interface{}) ([]byte, error) {
+	f.calls++
+	if f.calls <= f.fail {
+		return nil, &StatusError{StatusCode: 500, Status: "500 Internal Server Error"}
+	}
+	return []byte(method.String() + " " + path), nil
+}
+
+// TestClientTransport - calls are handed to the Transport and still retried
+func TestClientTransport(t *testing.T) {
+	var _ RestClient = &Client{}
+	f := &fakeTransport{fail: 1}
+	c := &Client{Transport: f, RetryCount: 1, RetryBackoff: time.Millisecond}
+
+	data, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "GET /rest/fake", string(data))
+	assert.Equal(t, 2, f.calls)
+
+	f.fail = 10
+	_, err = c.RestAPICall(PUT, "/rest/fake", nil)
+	var serr *StatusError
+	assert.True(t, errors.As(err, &serr))
+	assert.Equal(t, 3, f.calls)
+}
+
+// roundTripperFunc - adapt a func to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestClientHTTPClient - an injected http client is used for requests
+func TestClientHTTPClient(t *testing.T) {
+	var seen []string
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		seen = append(seen, r.Method+" "+r.URL.String())
+		return &http.Response{
+			StatusCode: 200,
+			Status:     "200 OK",
+			Body:       ioutil.NopCloser(strings.NewReader(`{"ok":true}`)),
+			Header:     make(http.Header),
+			Request:    r,
+		}, nil
+	})
+	var c *Client
+	c = c.NewClient("user", "key", "https://appliance.invalid", WithRoundTripper(rt))
+	assert.NotNil(t, c.HTTPClient)
+
+	data, err := c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(data))
+	assert.Equal(t, []string{"GET https://appliance.invalid/rest/version"}, seen)
+
+	hc := &http.Client{Transport: rt, Timeout: time.Minute}
+	c = c.NewClient("user", "key", "https://appliance.invalid", WithHTTPClient(hc))
+	assert.Equal(t, hc, c.HTTPClient)
+}
+
+// TestClientSSLVerify - SSLVerify and SetCACert reach the tls config of the request
+func TestClientSSLVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	var c *Client
+	c = c.NewClient("user", "key", ts.URL)
+	assert.True(t, c.SSLVerify)
+	_, err := c.RestAPICall(GET, "/rest/version", nil)
+	assert.Error(t, err, "self-signed certificate should not verify")
+
+	c.SetSSLVerify(false)
+	_, err = c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "SetSSLVerify(false) threw error -> %s", err)
+
+	c.SetSSLVerify(true)
+	assert.Error(t, c.SetCACert([]byte("not a certificate")))
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	assert.NoError(t, c.SetCACert(ca))
+	assert.False(t, c.TLSConfig().InsecureSkipVerify)
+	_, err = c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "SetCACert threw error -> %s", err)
+}
+
+// TestClientClone verify clones keep their own headers and query string and can
+// call concurrently, run with -race
+func TestClientClone(t *testing.T) {
+	f := &countTransport{}
+	c := &Client{Transport: f}
+	c.SetAuthHeaderOptions(map[string]string{"auth": "session1"})
+	c.SetQueryString(map[string]interface{}{"filter": "name='a'"})
+	clone := c.Clone()
+	clone.Option.Headers["auth"] = "session2"
+	clone.Option.Query["filter"] = "name='b'"
+	assert.Equal(t, "session1", c.Option.Headers["auth"])
+	assert.Equal(t, "name='a'", c.Option.Query["filter"])
+	assert.Nil(t, (&Client{}).Clone().Option.Headers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := c.Clone()
+			client.SetQueryString(map[string]interface{}{"start": strconv.Itoa(i)})
+			data, err := client.RestAPICall(GET, "/rest/fake", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, "/rest/fake?start="+strconv.Itoa(i), string(data))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 8, f.count())
+}
+
+// countTransport - answers with the path it got, safe for concurrent calls
+type countTransport struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *countTransport) RestAPICall(method Method, path string, options interface{}) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return []byte(path), nil
+}
+
+func (f *countTransport) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
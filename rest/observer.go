@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// metric names reported by the rest package
+const (
+	MetricRestCalls        = "oneview_rest_calls_total"
+	MetricRestErrors       = "oneview_rest_errors_total"
+	MetricRestCallDuration = "oneview_rest_call_duration_seconds"
+)
+
+// Observer - metrics interface called at key points of the package, replace it
+// with SetObserver to route metrics into Prometheus or another collector
+type Observer interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveDuration(name string, d time.Duration, labels map[string]string)
+}
+
+// observer is the Observer used by the package, metrics are dropped by default
+var observer Observer = noopObserver{}
+
+// SetObserver - set the Observer used by the package, nil drops all metrics
+func SetObserver(o Observer) {
+	if o == nil {
+		o = noopObserver{}
+	}
+	observer = o
+}
+
+// GetObserver - get the Observer used by the package
+func GetObserver() Observer {
+	return observer
+}
+
+// noopObserver - Observer that drops all metrics
+type noopObserver struct{}
+
+func (noopObserver) IncCounter(name string, labels map[string]string)                       {}
+func (noopObserver) ObserveDuration(name string, d time.Duration, labels map[string]string) {}
+
+// observeRestCall - report a single rest call, labelled with the method and the
+// status code of a failed call, "ok" on success and "error" when the call got no answer
+func observeRestCall(method Method, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+		var serr *StatusError
+		if errors.As(err, &serr) {
+			status = strconv.Itoa(serr.StatusCode)
+		}
+	}
+	labels := map[string]string{"method": method.String(), "status": status}
+	observer.IncCounter(MetricRestCalls, labels)
+	observer.ObserveDuration(MetricRestCallDuration, time.Since(start), labels)
+	if err != nil {
+		observer.IncCounter(MetricRestErrors, labels)
+	}
+}
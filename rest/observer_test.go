@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordObserver - Observer keeping the counters and durations it was given
+type recordObserver struct {
+	mu        sync.Mutex
+	counters  map[string]int
+	durations map[string]int
+}
+
+func newRecordObserver() *recordObserver {
+	return &recordObserver{counters: map[string]int{}, durations: map[string]int{}}
+}
+
+func (o *recordObserver) IncCounter(name string, labels map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.counters[name+" "+labels["method"]+" "+labels["status"]]++
+}
+
+func (o *recordObserver) ObserveDuration(name string, d time.Duration, labels map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.durations[name+" "+labels["method"]+" "+labels["status"]]++
+}
+
+// TestSetObserver verify every rest call attempt is observed, with the status of failures
+func TestSetObserver(t *testing.T) {
+	defer SetObserver(GetObserver())
+	o := newRecordObserver()
+	SetObserver(o)
+	f := &fakeTransport{fail: 1}
+	c := &Client{Transport: f, RetryCount: 1, RetryBackoff: time.Millisecond}
+	_, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		MetricRestCalls + " GET 500":  1,
+		MetricRestErrors + " GET 500": 1,
+		MetricRestCalls + " GET ok":   1,
+	}, o.counters)
+	assert.Equal(t, map[string]int{
+		MetricRestCallDuration + " GET 500": 1,
+		MetricRestCallDuration + " GET ok":  1,
+	}, o.durations)
+
+	SetObserver(nil)
+	_, err = c.RestAPICall(GET, "/rest/fake", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, o.counters[MetricRestCalls+" GET ok"])
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// connection pool defaults of the http client made by the Client, used when the
+// Client setting is 0
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 10
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// connPool - http client kept by the Client so calls reuse their connections,
+// rebuilt when the settings it was made with change
+type connPool struct {
+	settings poolSettings
+	client   *http.Client
+}
+
+// poolMu - guards the pool of every Client, Clone copies it under the lock as well,
+// one lock as clones share their pool
+var poolMu sync.Mutex
+
+// poolSettings - the Client settings the http client of a connPool is made with
+type poolSettings struct {
+	sslVerify           bool
+	rootCAs             *x509.CertPool
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+}
+
+// WithConnPool - tune the idle connections kept alive to the appliance, 0 keeps the
+// default, see DefaultMaxIdleConns, DefaultMaxIdleConnsPerHost and DefaultIdleConnTimeout
+func WithConnPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.MaxIdleConns = maxIdleConns
+		c.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		c.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// getHTTPClient - the HTTPClient when set, otherwise the pooled http client for the
+// current settings, clones made after the first call share its connections
+func (c *Client) getHTTPClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	settings := poolSettings{
+		sslVerify:           c.SSLVerify,
+		rootCAs:             c.RootCAs,
+		maxIdleConns:        c.MaxIdleConns,
+		maxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		idleConnTimeout:     c.IdleConnTimeout,
+	}
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	if c.pool != nil && c.pool.settings == settings {
+		return c.pool.client
+	}
+	tr := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     c.TLSConfig(),
+		MaxIdleConns:        orDefault(settings.maxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost: orDefault(settings.maxIdleConnsPerHost, DefaultMaxIdleConnsPerHost),
+		IdleConnTimeout:     DefaultIdleConnTimeout,
+	}
+	if settings.idleConnTimeout > 0 {
+		tr.IdleConnTimeout = settings.idleConnTimeout
+	}
+	if c.pool != nil {
+		// the settings changed, the connections of the old pool aren't reused
+		c.pool.client.CloseIdleConnections()
+	}
+	c.pool = &connPool{settings: settings, client: &http.Client{Transport: tr}}
+	return c.pool.client
+}
+
+// CloseIdleConnections - close the idle connections kept to the appliance, of the
+// HTTPClient when set, clones sharing them lose them too
+func (c *Client) CloseIdleConnections() {
+	if c.HTTPClient != nil {
+		c.HTTPClient.CloseIdleConnections()
+		return
+	}
+	poolMu.Lock()
+	pool := c.pool
+	poolMu.Unlock()
+	if pool != nil {
+		pool.client.CloseIdleConnections()
+	}
+}
+
+// orDefault - n, or def when n isn't set
+func orDefault(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClientConnPool verify calls reuse their connection, and a clone shares it
+func TestClientConnPool(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	var c *Client
+	c = c.NewClient("user", "key", ts.URL, WithConnPool(10, 2, time.Minute))
+	c.SetSSLVerify(false)
+	for i := 0; i < 5; i++ {
+		_, err := c.RestAPICall(GET, "/rest/version", nil)
+		assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	}
+	_, err := c.Clone().RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&conns))
+
+	// closed idle connections are made again
+	c.CloseIdleConnections()
+	_, err = c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&conns))
+	(&Client{}).CloseIdleConnections()
+
+	tr := c.getHTTPClient().Transport.(*http.Transport)
+	assert.Equal(t, 10, tr.MaxIdleConns)
+	assert.Equal(t, 2, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, tr.IdleConnTimeout)
+
+	// changing a setting gets a new pool
+	c.MaxIdleConnsPerHost = 0
+	tr = c.getHTTPClient().Transport.(*http.Transport)
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+	assert.True(t, tr.TLSClientConfig.InsecureSkipVerify)
+}
+
+// TestClientConnPoolConcurrent verify one Client, and its clones, can be used from
+// several goroutines, run with -race
+func TestClientConnPoolConcurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{Endpoint: ts.URL}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := c
+			if i%2 == 1 {
+				client = c.Clone()
+			}
+			for j := 0; j < 5; j++ {
+				_, err := client.RestAPICall(GET, "/rest/version", nil)
+				assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+			}
+			c.CloseIdleConnections()
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestClientConnPoolReplaced verify the idle connections of a pool replaced after a
+// settings change are closed
+func TestClientConnPoolReplaced(t *testing.T) {
+	var conns, closed int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			atomic.AddInt32(&conns, 1)
+		case http.StateClosed:
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	c := &Client{Endpoint: ts.URL}
+	_, err := c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	c.MaxIdleConns = 5
+	_, err = c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&conns))
+	for wait := 0; wait < 100 && atomic.LoadInt32(&closed) == 0; wait++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closed), "the old pool connection is closed")
+}
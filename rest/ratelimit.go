@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter - token bucket holding a single token, spaces calls interval apart
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait - block until the next call is allowed or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// SetRequestsPerSecond - limit the client to n calls a second, retries included,
+// 0 or less removes the limit. Copies of the client share the limit.
+func (c *Client) SetRequestsPerSecond(n float64) {
+	if n <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = &rateLimiter{interval: time.Duration(float64(time.Second) / n)}
+}
+
+// WithRequestsPerSecond - limit the client to n calls a second, see SetRequestsPerSecond
+func WithRequestsPerSecond(n float64) ClientOption {
+	return func(c *Client) {
+		c.SetRequestsPerSecond(n)
+	}
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequestsPerSecond - calls are spaced out and copies share the limit
+func TestRequestsPerSecond(t *testing.T) {
+	f := &fakeTransport{}
+	c := &Client{Transport: f}
+	c.ApplyOptions(WithRequestsPerSecond(20))
+	copied := *c
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.RestAPICall(GET, "/rest/version", nil)
+		assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+		_, err = copied.RestAPICall(GET, "/rest/version", nil)
+		assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+	}
+	assert.True(t, time.Since(start) >= 250*time.Millisecond, "6 calls at 20/s took %s", time.Since(start))
+	assert.Equal(t, 6, f.calls)
+
+	c.SetRequestsPerSecond(0)
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		c.RestAPICall(GET, "/rest/version", nil)
+	}
+	assert.True(t, time.Since(start) < 50*time.Millisecond, "unlimited calls took %s", time.Since(start))
+}
+
+// TestRequestsPerSecondContext - waiting on the limit ends with the context
+func TestRequestsPerSecondContext(t *testing.T) {
+	f := &fakeTransport{}
+	c := &Client{Transport: f}
+	c.SetRequestsPerSecond(0.1)
+	_, err := c.RestAPICall(GET, "/rest/version", nil)
+	assert.NoError(t, err, "RestAPICall threw error -> %s", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = c.RestAPICallContext(ctx, GET, "/rest/version", nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, f.calls)
+}
@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RedactedFields - json fields and headers whose values are never logged,
+// matched case insensitively, add to it for site specific secrets
+var RedactedFields = []string{
+	"auth",
+	"authorization",
+	"password",
+	"sessionID",
+	"Session-ID",
+	"X-Auth-Token",
+}
+
+// redactedValue - logged in place of a redacted value
+const redactedValue = "REDACTED"
+
+// isRedacted - true when the field or header named k is in RedactedFields
+func isRedacted(k string) bool {
+	for _, f := range RedactedFields {
+		if strings.EqualFold(f, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact - get body with the values of RedactedFields replaced at any depth,
+// bodies that aren't json are not logged at all
+func Redact(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("<%d bytes, not json>", len(body))
+	}
+	data, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes, not json>", len(body))
+	}
+	return string(data)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			if isRedacted(k) {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = redactValue(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e)
+		}
+	}
+	return v
+}
+
+// RedactHeaders - get a copy of headers with the values of RedactedFields replaced
+func RedactHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if isRedacted(k) {
+			v = redactedValue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// WithBodyLogging - debug log request and response bodies of every call, with
+// RedactedFields removed, see Client.LogBodies
+func WithBodyLogging() ClientOption {
+	return func(c *Client) {
+		c.LogBodies = true
+	}
+}
+
+// logRequest - debug log the call about to be made, redacted
+func (c *Client) logRequest(ctx context.Context, method Method, path string, options interface{}) {
+	var body string
+	if options != nil {
+		data, err := json.Marshal(options)
+		if err != nil {
+			body = fmt.Sprintf("<%T not json: %s>", options, err)
+		} else {
+			body = Redact(data)
+		}
+	}
+	log.Debugf("[%s] Request %s %s headers %v body %s", CorrelationID(ctx), method, path, RedactHeaders(c.Option.Headers), body)
+}
+
+// logResponse - debug log the answer to a call, redacted
+func (c *Client) logResponse(ctx context.Context, method Method, path string, data []byte, err error) {
+	if err != nil {
+		log.Debugf("[%s] Response %s %s error %s", CorrelationID(ctx), method, path, err)
+		return
+	}
+	log.Debugf("[%s] Response %s %s body %s", CorrelationID(ctx), method, path, Redact(data))
+}
+
+// logDecoded - debug log the start of a streamed answer as decoded into v, redacted,
+// the body itself was never held
+func (c *Client) logDecoded(ctx context.Context, method Method, path string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Debugf("[%s] Response %s %s streamed into %T", CorrelationID(ctx), method, path, v)
+		return
+	}
+	body := Redact(data)
+	if len(body) > sniffLength {
+		body = body[:sniffLength] + "..."
+	}
+	log.Debugf("[%s] Response %s %s streamed body %s", CorrelationID(ctx), method, path, body)
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRedact - sensitive values are replaced at any depth
+func TestRedact(t *testing.T) {
+	assert.Equal(t, `{"authLoginDomain":"LOCAL","password":"REDACTED","userName":"admin"}`,
+		Redact([]byte(`{"userName":"admin","password":"secret","authLoginDomain":"LOCAL"}`)))
+	assert.Equal(t, `[{"nested":{"SessionID":"REDACTED"}}]`, Redact([]byte(`[{"nested":{"SessionID":"abc"}}]`)))
+	assert.Equal(t, "<6 bytes, not json>", Redact([]byte("secret")))
+	assert.Equal(t, "", Redact(nil))
+	assert.Equal(t, map[string]string{"auth": "REDACTED", "X-API-Version": "120"},
+		RedactHeaders(map[string]string{"auth": "abc", "X-API-Version": "120"}))
+}
+
+// TestBodyLogging - bodies are only logged when asked for, and never with secrets
+func TestBodyLogging(t *testing.T) {
+	defer SetLogger(GetLogger())
+	var out bytes.Buffer
+	SetLogger(&StdLogger{Out: &out, Err: &out, Debug: true})
+
+	c := &Client{Transport: &fakeTransport{}}
+	c.SetAuthHeaderOptions(map[string]string{"auth": "session-key"})
+	body := map[string]string{"userName": "admin", "password": "secret"}
+	c.RestAPICall(POST, "/rest/login-sessions", body)
+	assert.NotContains(t, out.String(), "Request POST")
+
+	c.ApplyOptions(WithBodyLogging())
+	c.RestAPICall(POST, "/rest/login-sessions", body)
+	assert.Contains(t, out.String(), `Request POST /rest/login-sessions headers map[auth:REDACTED] body {"password":"REDACTED","userName":"admin"}`)
+	assert.Contains(t, out.String(), "Response POST /rest/login-sessions body <")
+	assert.NotContains(t, out.String(), "secret")
+	assert.NotContains(t, out.String(), "session-key")
+
+	// streamed answers are logged as decoded, cut short
+	out.Reset()
+	c = bodyClient(`{"sessionID":"session-key","members":[`+strings.Repeat(`"a",`, 200)+`"a"]}`, "application/json")
+	c.ApplyOptions(WithBodyLogging())
+	var v map[string]interface{}
+	assert.NoError(t, c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v))
+	assert.Contains(t, out.String(), `Response GET /rest/tasks streamed body {"members":["a",`)
+	assert.Contains(t, out.String(), "...")
+	assert.NotContains(t, out.String(), "session-key")
+}
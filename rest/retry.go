@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryBackoff - wait before the first retry when Client.RetryBackoff is not set,
+// DefaultMaxRetryBackoff - longest wait between retries when Client.MaxRetryBackoff is not set
+const (
+	DefaultRetryBackoff    = time.Second
+	DefaultMaxRetryBackoff = 30 * time.Second
+)
+
+// StatusError - error returned when the appliance answers with a non ok status
+type StatusError struct {
+	StatusCode int           // 503
+	Status     string        // "503 Service Unavailable"
+	Details    string        // details from the appliance error body
+	RetryAfter time.Duration // parsed Retry-After header, 0 when not sent
+}
+
+// Error - keep the message used before StatusError existed, callers match on it
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Error in response: %s\n Response Status: %s", e.Details, e.Status)
+}
+
+// Temporary - true when the status is worth retrying, 5xx from a busy appliance
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= 500
+}
+
+// transportError - error from the http client, the request never got an answer
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// isRetryable - only idempotent GETs are retried, and only for transport
+// errors or 5xx responses, 4xx fail fast
+func isRetryable(method Method, err error) bool {
+	if method != GET || err == nil {
+		return false
+	}
+	var serr *StatusError
+	if errors.As(err, &serr) {
+		return serr.Temporary()
+	}
+	var terr *transportError
+	return errors.As(err, &terr)
+}
+
+// getRetryWait - wait before retry attempt, doubles RetryBackoff each attempt up
+// to MaxRetryBackoff, unless the appliance asked for a Retry-After on 503
+func (c *Client) getRetryWait(attempt int, err error) time.Duration {
+	var serr *StatusError
+	if errors.As(err, &serr) && serr.StatusCode == http.StatusServiceUnavailable && serr.RetryAfter > 0 {
+		return serr.RetryAfter
+	}
+	wait := c.RetryBackoff
+	if wait <= 0 {
+		wait = DefaultRetryBackoff
+	}
+	ceiling := c.MaxRetryBackoff
+	if ceiling <= 0 {
+		ceiling = DefaultMaxRetryBackoff
+	}
+	// stop doubling at the ceiling so large attempts can't overflow
+	for i := 0; i < attempt && wait < ceiling; i++ {
+		wait *= 2
+	}
+	if wait > ceiling {
+		wait = ceiling
+	}
+	return wait
+}
+
+// parseRetryAfter - Retry-After is either delay seconds or an http date
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// get a client with a server that fails the first failures calls with status
+func getTestRetryClient(failures int32, status int, header map[string]string) (*Client, *httptest.Server, *int32) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{"details":"fake failure"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	c := &Client{Endpoint: ts.URL, RetryCount: 3, RetryBackoff: time.Millisecond}
+	return c, ts, &calls
+}
+
+// TestRestAPICallRetry - 5xx on GET is retried until it succeeds
+func TestRestAPICallRetry(t *testing.T) {
+	c, ts, calls := getTestRetryClient(2, http.StatusInternalServerError, nil)
+	defer ts.Close()
+
+	data, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(data))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+// TestRestAPICallRetryExhausted - the last StatusError is returned
+func TestRestAPICallRetryExhausted(t *testing.T) {
+	c, ts, calls := getTestRetryClient(10, http.StatusBadGateway, nil)
+	defer ts.Close()
+
+	_, err := c.RestAPICall(GET, "/rest/fake", nil)
+	var serr *StatusError
+	assert.True(t, errors.As(err, &serr))
+	assert.Equal(t, http.StatusBadGateway, serr.StatusCode)
+	assert.Equal(t, "fake failure", serr.Details)
+	assert.True(t, strings.Contains(err.Error(), "Response Status: 502 Bad Gateway"))
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+// TestRestAPICallNoRetry - 4xx, non GET and RetryCount 0 make one attempt
+func TestRestAPICallNoRetry(t *testing.T) {
+	c, ts, calls := getTestRetryClient(10, http.StatusNotFound, nil)
+	_, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "404 Not Found"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	ts.Close()
+
+	c, ts, calls = getTestRetryClient(10, http.StatusInternalServerError, nil)
+	_, err = c.RestAPICall(PUT, "/rest/fake", nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	c.RetryCount = 0
+	_, err = c.RestAPICall(GET, "/rest/fake", nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	ts.Close()
+}
+
+// TestRestAPICallRetryAfter - 503 waits for the Retry-After the appliance sent
+func TestRestAPICallRetryAfter(t *testing.T) {
+	c, ts, calls := getTestRetryClient(1, http.StatusServiceUnavailable, map[string]string{"Retry-After": "1"})
+	defer ts.Close()
+
+	start := time.Now()
+	_, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= time.Second, "expected to wait for Retry-After")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+// TestRestAPICallRetryTransport - connection errors are retried
+func TestRestAPICallRetryTransport(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+	c := &Client{Endpoint: ts.URL, RetryCount: 2, RetryBackoff: time.Millisecond}
+
+	_, err := c.RestAPICall(GET, "/rest/fake", nil)
+	assert.Error(t, err)
+	assert.True(t, isRetryable(GET, err))
+	var serr *StatusError
+	assert.False(t, errors.As(err, &serr))
+}
+
+// TestGetRetryWait - backoff doubles up to the ceiling, Retry-After wins on 503
+func TestGetRetryWait(t *testing.T) {
+	c := &Client{}
+	assert.Equal(t, DefaultRetryBackoff, c.getRetryWait(0, errors.New("reset")))
+	c.RetryBackoff = 100 * time.Millisecond
+	assert.Equal(t, 400*time.Millisecond, c.getRetryWait(2, errors.New("reset")))
+	assert.Equal(t, DefaultMaxRetryBackoff, c.getRetryWait(100, errors.New("reset")))
+	c.MaxRetryBackoff = time.Second
+	assert.Equal(t, time.Second, c.getRetryWait(4, errors.New("reset")))
+	serr := &StatusError{StatusCode: 503, RetryAfter: 5 * time.Second}
+	assert.Equal(t, 5*time.Second, c.getRetryWait(2, serr))
+	assert.Equal(t, 7*time.Second, parseRetryAfter("7"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+}
+
+// TestRestAPICallContext - a cancelled context stops the call and any retries
+func TestRestAPICallContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	c := &Client{Endpoint: ts.URL, RetryCount: 3, RetryBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.RestAPICallContext(ctx, GET, "/rest/fake", nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %s", err)
+	assert.True(t, time.Since(start) < time.Minute)
+}
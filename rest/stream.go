@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/HewlettPackard/oneview-golang/utils"
+)
+
+// ErrBodyTooLarge - a response body is larger than the Client MaxBodySize
+var ErrBodyTooLarge = errors.New("Response body too large")
+
+// sniffLength - bytes of a streamed body looked at to tell an html page
+const sniffLength = 512
+
+// maxBodyReader - reader failing with ErrBodyTooLarge past max bytes
+type maxBodyReader struct {
+	r    io.Reader
+	max  int64
+	left int64
+}
+
+func (m *maxBodyReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.left -= int64(n)
+	if m.left < 0 {
+		return n, fmt.Errorf("%w, more than %d bytes", ErrBodyTooLarge, m.max)
+	}
+	return n, err
+}
+
+// limitBody - body limited to MaxBodySize, as is when there is no limit
+func (c *Client) limitBody(body io.Reader) io.Reader {
+	if c.MaxBodySize <= 0 {
+		return body
+	}
+	return &maxBodyReader{r: io.LimitReader(body, c.MaxBodySize+1), max: c.MaxBodySize, left: c.MaxBodySize}
+}
+
+// RestAPICallDecode - RestAPICallContext decoding the response body into v as it
+// is read, without holding the whole body, an empty body leaves v as is, GETs
+// are retried as for RestAPICall
+func (c *Client) RestAPICallDecode(ctx context.Context, method Method, path string, options interface{}, v interface{}) error {
+	return c.retry(ctx, method, path, func(ctx context.Context) error {
+		return c.restAPIDecode(ctx, method, path, options, v)
+	})
+}
+
+// restAPIDecode - make a single rest call decoding the response into v
+func (c *Client) restAPIDecode(ctx context.Context, method Method, path string, options interface{}, v interface{}) error {
+	log.Debugf("[%s] RestAPICall %s - %s%s, streamed", CorrelationID(ctx), method, utils.Sanatize(c.Endpoint), path)
+	if c.LogBodies {
+		c.logRequest(ctx, method, path, options)
+	}
+	start := time.Now()
+	err := c.sendDecode(ctx, method, path, options, v)
+	observeRestCall(method, start, err)
+	if c.LogBodies {
+		if err != nil {
+			c.logResponse(ctx, method, path, nil, err)
+		} else {
+			c.logDecoded(ctx, method, path, v)
+		}
+	}
+	return err
+}
+
+// sendDecode - send a single rest call to the Transport or over http, decoding
+// the body into v
+func (c *Client) sendDecode(ctx context.Context, method Method, path string, options interface{}, v interface{}) error {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return err
+		}
+	}
+	if c.Transport != nil {
+		// the Transport answers in memory, there is nothing to stream
+		data, err := c.sendTransport(ctx, method, path, options)
+		if err != nil || len(data) == 0 {
+			return err
+		}
+		if c.MaxBodySize > 0 && int64(len(data)) > c.MaxBodySize {
+			return fmt.Errorf("%w, more than %d bytes", ErrBodyTooLarge, c.MaxBodySize)
+		}
+		return json.Unmarshal(data, v)
+	}
+	resp, err := c.do(ctx, method, path, options)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body := bufio.NewReaderSize(c.limitBody(resp.Body), sniffLength)
+	start, err := body.Peek(sniffLength)
+	if err != nil && err != io.EOF && !errors.Is(err, ErrBodyTooLarge) {
+		return &transportError{err}
+	}
+	if err := checkHTMLResponse(resp.Header.Get("Content-Type"), start); err != nil {
+		return err
+	}
+	err = json.NewDecoder(body).Decode(v)
+	if err == io.EOF && len(start) == 0 {
+		return nil
+	}
+	return err
+}
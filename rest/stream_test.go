@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bodyClient - client answering every call with body and content type
+func bodyClient(body, contentType string) *Client {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		header.Set("Content-Type", contentType)
+		return &http.Response{
+			StatusCode: 200,
+			Status:     "200 OK",
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Header:     header,
+			Request:    r,
+		}, nil
+	})
+	var c *Client
+	return c.NewClient("user", "key", "https://appliance.invalid", WithRoundTripper(rt))
+}
+
+// TestRestAPICallDecode - the body is decoded into the target, html pages and empty
+// bodies are told apart
+func TestRestAPICallDecode(t *testing.T) {
+	var v struct {
+		Members []int `json:"members"`
+	}
+	c := bodyClient(`{"members":[1,2,3]}`, "application/json")
+	err := c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, v.Members)
+
+	c = bodyClient("", "application/json")
+	assert.NoError(t, c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v))
+	assert.Equal(t, []int{1, 2, 3}, v.Members)
+
+	c = bodyClient("<html><body>Gateway Timeout</body></html>", "text/html")
+	err = c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v)
+	assert.True(t, errors.Is(err, ErrNonJSONResponse), "expected non json, got %s", err)
+
+	c = &Client{Transport: &fakeTransport{}}
+	err = c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v)
+	assert.Error(t, err, "the fake transport doesn't answer json")
+}
+
+// TestMaxBodySize - bodies over MaxBodySize fail, streamed or not
+func TestMaxBodySize(t *testing.T) {
+	body := `{"members":[` + strings.Repeat("1,", 1000) + `1]}`
+	c := bodyClient(body, "application/json")
+	c.MaxBodySize = 100
+	c.RetryCount = 2
+	_, err := c.RestAPICall(GET, "/rest/tasks", nil)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge), "expected too large, got %s", err)
+
+	var v struct {
+		Members []int `json:"members"`
+	}
+	err = c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge), "expected too large, got %s", err)
+
+	c.MaxBodySize = int64(len(body))
+	data, err := c.RestAPICall(GET, "/rest/tasks", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(data))
+	assert.NoError(t, c.RestAPICallDecode(context.Background(), GET, "/rest/tasks", nil, &v))
+	assert.Len(t, v.Members, 1001)
+}
@@ -2,8 +2,6 @@ package testconfig
 
 import (
 	"os"
-
-	"github.com/docker/machine/libmachine/log"
 )
 
 // test case objects
@@ -59,7 +57,7 @@ func (tc *TestConfig) IsTestEnabled(tc_name string) bool {
 		return d.Enabled
 	}
 	log.Infof("tc no name -> %+v", tc.GetTestCases("foo"))
-	log.Warn("Test config is using default true for enablement.")
+	log.Warnf("Test config is using default true for enablement.")
 	return true
 }
 
@@ -8,9 +8,12 @@ import (
 	"io/ioutil"
 	"os"
 
-	"github.com/docker/machine/libmachine/log"
+	"github.com/HewlettPackard/oneview-golang/rest"
 )
 
+// log is the Logger used by the testconfig package, the one set with rest.SetLogger
+var log = rest.SharedLogger()
+
 //
 // test case objects
 //  see testcases.go for test case methods
@@ -56,9 +59,6 @@ func (tc *TestConfig) GetTestingConfiguration(config_name string) {
 		Pkg           PackageInfo
 		test_data_dir string
 	)
-	if os.Getenv("ONEVIEW_DEBUG") == "true" {
-		log.SetDebug(true)
-	}
 	package_root = os.Getenv("TESTCONFIG_PACKAGE_ROOT_PATH")
 	if found, package_full_dir := Pkg.GetPackageRootDir(package_root); found == true {
 		test_data_dir = Pkg.JoinPath([]string{package_full_dir,